@@ -0,0 +1,109 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ArgWrites are the values written into the pointer, slice and map arguments of calls of one mock method before
+// its stub is invoked, generated mocks hold one per method with such arguments (MockX_Method_ArgWrites).
+// The zero value writes nothing and is ready to use
+type ArgWrites struct {
+	mu     sync.Mutex
+	values map[int]interface{}
+}
+
+// Set writes value into the nth argument, counting from 0. A pointer argument is set to value, or to what value
+// points to, numbers are converted to the type pointed to. Slice arguments get the elements of value copied in,
+// map arguments get its entries added
+func (a *ArgWrites) Set(n int, value interface{}) *ArgWrites {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.values == nil {
+		a.values = make(map[int]interface{})
+	}
+	a.values[n] = value
+	return a
+}
+
+// Reset stops writing into arguments
+func (a *ArgWrites) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values = nil
+}
+
+// Apply writes the configured values into args, the arguments of a call
+func (a *ArgWrites) Apply(args []interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ns := make([]int, 0, len(a.values))
+	for n := range a.values {
+		ns = append(ns, n)
+	}
+	sort.Ints(ns)
+	for _, n := range ns {
+		if n < 0 || n >= len(args) {
+			return fmt.Errorf("argument %d does not exist", n)
+		}
+		if err := writeArg(args[n], a.values[n]); err != nil {
+			return fmt.Errorf("argument %d: %s", n, err)
+		}
+	}
+	return nil
+}
+
+func writeArg(arg, value interface{}) error {
+	dst := reflect.ValueOf(arg)
+	v := reflect.ValueOf(value)
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			return fmt.Errorf("nil %s cannot be written into", dst.Type())
+		}
+		el := dst.Elem()
+		if v.IsValid() && v.Type() == dst.Type() {
+			if v.IsNil() {
+				return fmt.Errorf("nil %s cannot be written", v.Type())
+			}
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			el.Set(reflect.Zero(el.Type()))
+			return nil
+		}
+		if !v.Type().AssignableTo(el.Type()) {
+			if !numeric(v.Kind()) || !numeric(el.Kind()) || !v.Type().ConvertibleTo(el.Type()) {
+				return fmt.Errorf("%s cannot be written into %s", v.Type(), dst.Type())
+			}
+			v = v.Convert(el.Type())
+		}
+		el.Set(v)
+	case reflect.Slice:
+		if !v.IsValid() || v.Kind() != reflect.Slice || !v.Type().Elem().AssignableTo(dst.Type().Elem()) {
+			return fmt.Errorf("%T cannot be copied into %s", value, dst.Type())
+		}
+		reflect.Copy(dst, v)
+	case reflect.Map:
+		if dst.IsNil() {
+			return fmt.Errorf("nil %s cannot be written into", dst.Type())
+		}
+		if !v.IsValid() || v.Kind() != reflect.Map || !v.Type().Key().AssignableTo(dst.Type().Key()) ||
+			!v.Type().Elem().AssignableTo(dst.Type().Elem()) {
+			return fmt.Errorf("%T cannot be added to %s", value, dst.Type())
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+		}
+	default:
+		return fmt.Errorf("%T is not a pointer, slice or map", arg)
+	}
+	return nil
+}
+
+func numeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Complex128
+}
@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type order struct {
+	ID    string
+	Items int
+}
+
+func Test_argWrites(t *testing.T) {
+	f := float32(1)
+	o := &order{}
+	s := make([]int, 3)
+	m := map[string]int{"a": 1}
+	w := (&ArgWrites{}).Set(0, 3.5).Set(1, &order{ID: "o1", Items: 2}).Set(2, []int{7, 8}).Set(3, map[string]int{"b": 2})
+	if err := w.Apply([]interface{}{&f, o, s, m}); err != nil {
+		t.Fatal(err)
+	}
+	if f != 3.5 || *o != (order{ID: "o1", Items: 2}) || s[0] != 7 || s[1] != 8 || s[2] != 0 || m["a"] != 1 || m["b"] != 2 {
+		t.Errorf("arguments should have been written, but were %v %+v %v %v", f, *o, s, m)
+	}
+	w.Reset()
+	if err := w.Set(0, order{ID: "o2"}).Apply([]interface{}{o}); err != nil || o.ID != "o2" {
+		t.Errorf("value should have been written into pointer, but was %+v (%v)", *o, err)
+	}
+	for _, c := range []struct {
+		n     int
+		value interface{}
+		arg   interface{}
+		want  string
+	}{
+		{0, "x", &f, "argument 0: string cannot be written into *float32"},
+		{0, 1, (*float32)(nil), "argument 0: nil *float32 cannot be written into"},
+		{0, 1, 2, "argument 0: int is not a pointer, slice or map"},
+		{0, []string{"a"}, s, "argument 0: []string cannot be copied into []int"},
+		{1, 1, &f, "argument 1 does not exist"},
+	} {
+		err := (&ArgWrites{}).Set(c.n, c.value).Apply([]interface{}{c.arg})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("should have failed with '%s', but was %v", c.want, err)
+		}
+	}
+}
+
+func Test_setsArg(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	var failure string
+	m := &MockL1{Defaults: &Defaults{Fail: func(format string, args ...interface{}) {
+		failure = fmt.Sprintf(format, args...)
+	}}}
+	m.LM3SetsArg(0, 2.5)
+	v := float32(1)
+	m.LM3(&v)
+	if v != 2.5 {
+		t.Errorf("argument should have been written, but was %v", v)
+	}
+	MockL1_LM3_Reset()
+	m.LM3SetsArg(0, "text")
+	m.LM3(&v)
+	if !strings.HasPrefix(failure, "MockL1_LM3 ") {
+		t.Errorf("argument of the wrong type should have failed, but got '%s'", failure)
+	}
+}
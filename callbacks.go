@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Callbacks are the argument lists a func argument of a mock method is invoked with on every call, generated
+// mocks hold one per func argument (MockX_Method_A1_Callbacks). The zero value invokes nothing and is ready to use
+type Callbacks struct {
+	mu          sync.Mutex
+	invocations [][]interface{}
+}
+
+// Add invokes the func argument with args on every call, after the invocations added before
+func (c *Callbacks) Add(args ...interface{}) *Callbacks {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invocations = append(c.invocations, args)
+	return c
+}
+
+// Reset stops invoking the func argument
+func (c *Callbacks) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invocations = nil
+}
+
+// Invoke calls fn with every configured argument list in order, nil arguments are passed as zero values. When fn
+// returns a non nil error as its last result, the remaining invocations are skipped and the error is returned
+func (c *Callbacks) Invoke(fn interface{}) error {
+	c.mu.Lock()
+	invocations := c.invocations
+	c.mu.Unlock()
+	f := reflect.ValueOf(fn)
+	if len(invocations) == 0 || !f.IsValid() || f.IsNil() {
+		return nil
+	}
+	t := f.Type()
+	for _, args := range invocations {
+		if len(args) != t.NumIn() {
+			return fmt.Errorf("callback %s invoked with %d arguments", t, len(args))
+		}
+		in := make([]reflect.Value, 0, len(args))
+		for i, a := range args {
+			v := reflect.ValueOf(a)
+			if !v.IsValid() {
+				v = reflect.Zero(t.In(i))
+			}
+			if !v.Type().AssignableTo(t.In(i)) {
+				return fmt.Errorf("callback %s invoked with %s as argument %d", t, v.Type(), i)
+			}
+			in = append(in, v)
+		}
+		out := f.Call(in)
+		if n := len(out); n > 0 && t.Out(n-1) == errorType && !out[n-1].IsNil() {
+			return out[n-1].Interface().(error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_callbacks(t *testing.T) {
+	c := &Callbacks{}
+	if err := c.Invoke(func(string) { t.Errorf("callback should NOT have been invoked") }); err != nil {
+		t.Errorf("no errors expected, but got %v", err)
+	}
+	var seen []string
+	fn := func(s string, e error) error {
+		seen = append(seen, s)
+		if s == "b" {
+			return errors.New("stop")
+		}
+		return e
+	}
+	c.Add("a", nil).Add("b", nil).Add("c", nil)
+	if err := c.Invoke(fn); err == nil || err.Error() != "stop" || strings.Join(seen, "") != "ab" {
+		t.Errorf("invocations should have stopped at the error, but were %v (%v)", seen, err)
+	}
+	if err := c.Invoke((func(string, error) error)(nil)); err != nil {
+		t.Errorf("nil callback should have been skipped, but was %v", err)
+	}
+	c.Reset()
+	c.Add(1, nil)
+	if err := c.Invoke(fn); err == nil || !strings.Contains(err.Error(), "invoked with int as argument 0") {
+		t.Errorf("wrong argument should have failed, but was %v", err)
+	}
+	c.Reset()
+	c.Add("a")
+	if err := c.Invoke(fn); err == nil || !strings.Contains(err.Error(), "invoked with 1 arguments") {
+		t.Errorf("wrong number of arguments should have failed, but was %v", err)
+	}
+}
+
+func Test_mockCallbacks(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	var failures []string
+	m := &MockBus{Defaults: &Defaults{Fail: func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}}}
+	m.WalkInvokesA1(Message{Name: "w"})
+	m.WalkInvokesA1(Message{Name: "skipped"})
+	names := ""
+	err := m.Walk(func(msg Message) error {
+		names += msg.Name
+		return errors.New("stop")
+	})
+	if names != "w" || err == nil || err.Error() != "stop" {
+		t.Errorf("callback error should have been returned after w, but were %s and %v", names, err)
+	}
+	m.EachInvokesA1(1, "e")
+	names = ""
+	m.Each(func(i int, s string) { names += fmt.Sprint(i, s) })
+	if names != "1e" {
+		t.Errorf("callback should have been invoked with 1 e, but was %s", names)
+	}
+	MockBus_Each_Reset()
+	MockBus_Each_A1_Callbacks.Add("wrong", 1)
+	failures = nil
+	m.Each(func(i int, s string) {})
+	if len(failures) == 0 || !strings.HasPrefix(failures[0], "MockBus_Each callback") {
+		t.Errorf("invocation without an error result should have failed the mock, but got %v", failures)
+	}
+}
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_defaults(t *testing.T) {
+	t.Cleanup(func() {
+		UnstubbedDefaults = &Defaults{}
+		ResetMocks()
+	})
+	one := 1
+	m := MockL1{Defaults: (&Defaults{}).Return((*string)(nil), "default").Return((**int)(nil), &one)}
+	if s, p := m.LM1(1, 2); s != "default" || p != &one {
+		t.Errorf("unstubbed call should have returned the defaults of the mock, but returned %s %v", s, p)
+	}
+	var failures []string
+	UnstubbedDefaults = &Defaults{Fail: func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}}
+	MockL3{}.LM3(1, 2)
+	if len(failures) != 1 || failures[0] != "MockL3_LM3 called with [1 2], but has no stub" {
+		t.Errorf("unstubbed call should have failed once, but got %v", failures)
+	}
+}
+
+func Test_defaultsReturnType(t *testing.T) {
+	for _, c := range []struct {
+		ptr, value interface{}
+		want       string
+	}{
+		{(*error)(nil), "x", "Defaults.Return: string is not assignable to results of type error"},
+		{"x", "x", "Defaults.Return: string should have been a pointer to the type of the results"},
+		{nil, "x", "Defaults.Return: <nil> should have been a pointer to the type of the results"},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != c.want {
+					t.Errorf("should have panicked with '%s', but was %v", c.want, r)
+				}
+			}()
+			(&Defaults{}).Return(c.ptr, c.value)
+		}()
+	}
+	d := (&Defaults{}).Return((*error)(nil), nil).Return((*error)(nil), fmt.Errorf("x"))
+	if len(d.Values) != 1 {
+		t.Errorf("nil and assignable values should have been accepted")
+	}
+}
@@ -0,0 +1,108 @@
+package mock
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Fault is injected into a call of a mock method before its stub is invoked
+type Fault struct {
+	// Err is returned as the method's error result, methods without one panic with it
+	Err error
+	// Panic, when not nil, is the value the call panics with
+	Panic interface{}
+	// Delay is slept before the fault is applied or the call proceeds
+	Delay time.Duration
+}
+
+// rule injects a fault into the calls it matches
+type rule struct {
+	match func(call int, args []interface{}) bool
+	fault Fault
+}
+
+// Faults are the fault injection rules of one mock method, generated mocks hold one per method (MockX_Method_Faults).
+// The first matching rule is applied. The zero value has no rules and is ready to use
+type Faults struct {
+	mu    sync.Mutex
+	calls int
+	rules []rule
+}
+
+// OnCall injects fault into the nth call, counting from 0
+func (f *Faults) OnCall(n int, fault Fault) *Faults {
+	return f.add(func(call int, args []interface{}) bool {
+		return call == n
+	}, fault)
+}
+
+// Always injects fault into every call
+func (f *Faults) Always(fault Fault) *Faults {
+	return f.add(func(call int, args []interface{}) bool {
+		return true
+	}, fault)
+}
+
+// WithProbability injects fault into calls with probability p, drawn from a source seeded with seed so runs repeat
+func (f *Faults) WithProbability(p float64, seed int64, fault Fault) *Faults {
+	r := rand.New(rand.NewSource(seed))
+	return f.add(func(call int, args []interface{}) bool {
+		return r.Float64() < p
+	}, fault)
+}
+
+// WhenArgs injects fault into calls whose arguments are deeply equal to args
+func (f *Faults) WhenArgs(args []interface{}, fault Fault) *Faults {
+	return f.add(func(call int, actual []interface{}) bool {
+		return reflect.DeepEqual(args, actual)
+	}, fault)
+}
+
+// Reset removes all rules and restarts counting calls
+func (f *Faults) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = 0
+	f.rules = nil
+}
+
+func (f *Faults) add(match func(call int, args []interface{}) bool, fault Fault) *Faults {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, rule{match: match, fault: fault})
+	return f
+}
+
+// Inject applies the first rule matching a call with args: it sleeps for the delay, panics with the panic value
+// and stores the error in err. It reports whether the call is to return without invoking the stub.
+// err is nil for methods without an error result
+func (f *Faults) Inject(args []interface{}, err *error) bool {
+	f.mu.Lock()
+	call := f.calls
+	f.calls++
+	var fault *Fault
+	for i := range f.rules {
+		if f.rules[i].match(call, args) {
+			fault = &f.rules[i].fault
+			break
+		}
+	}
+	f.mu.Unlock()
+	if fault == nil {
+		return false
+	}
+	time.Sleep(fault.Delay)
+	if fault.Panic != nil {
+		panic(fault.Panic)
+	}
+	if fault.Err == nil {
+		return false
+	}
+	if err == nil {
+		panic(fault.Err)
+	}
+	*err = fault.Err
+	return true
+}
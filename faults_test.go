@@ -0,0 +1,102 @@
+package mock
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_faults(t *testing.T) {
+	fail := errors.New("fail")
+	f := &Faults{}
+	f.OnCall(1, Fault{Err: fail}).WhenArgs([]interface{}{"bad"}, Fault{Err: fail})
+	var err error
+	if f.Inject([]interface{}{"good"}, &err) || err != nil {
+		t.Errorf("call 0 should NOT have been faulted")
+	}
+	if !f.Inject([]interface{}{"good"}, &err) || err != fail {
+		t.Errorf("call 1 should have been faulted, but got %v", err)
+	}
+	err = nil
+	if !f.Inject([]interface{}{"bad"}, &err) || err != fail {
+		t.Errorf("matching args should have been faulted, but got %v", err)
+	}
+	f.Reset()
+	if f.Inject([]interface{}{"bad"}, &err) {
+		t.Errorf("no rules should have remained after reset")
+	}
+}
+
+func Test_faultPanics(t *testing.T) {
+	f := (&Faults{}).Always(Fault{Panic: "boom"})
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("should have panicked with boom, but was %v", r)
+		}
+	}()
+	f.Inject(nil, nil)
+}
+
+func Test_faultProbability(t *testing.T) {
+	count := func() []bool {
+		f := (&Faults{}).WithProbability(0.5, 42, Fault{Err: errors.New("fail")})
+		faulted := make([]bool, 0)
+		for i := 0; i < 20; i++ {
+			var err error
+			faulted = append(faulted, f.Inject(nil, &err))
+		}
+		return faulted
+	}
+	first, second := count(), count()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("the same seed should have faulted the same calls, but got %v and %v", first, second)
+	}
+	n := 0
+	for _, b := range first {
+		if b {
+			n++
+		}
+	}
+	if n == 0 || n == 20 {
+		t.Errorf("about half of the calls should have been faulted, but %d were", n)
+	}
+}
+
+func Test_faultTemplate(t *testing.T) {
+	b := New("mock").(*builder)
+	info := populateInfo(Component{Name: "Store", Instance: &Store{}})
+	info.MockName = "MockStore"
+	info.PkgPath = "example.com/store"
+	ginfo := genInfo{EnclosingType: info, EnclosedTypes: map[reflect.Type]*typeInfo{info.Typ: info}, Record: true, Shared: true}
+	f := b.fileData(&ginfo)
+	if f.Runtime != "mock." || f.Mocks[0].Methods[0].Err != "ret1" {
+		t.Fatalf("runtime should have been qualified and ret1 the error, but were %s and %s", f.Runtime, f.Mocks[0].Methods[0].Err)
+	}
+	tmpl, err := b.parseTemplates(Classic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s strings.Builder
+	if err = tmpl.ExecuteTemplate(&s, "file", f); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"\"github.com/rvauradkar1/mockgen\"", "var MockStore_Load_Faults mock.Faults",
+		"if MockStore_Load_Faults.Inject([]interface{}{a1 }, &ret1) {"} {
+		if !strings.Contains(s.String(), want) {
+			t.Errorf("should have contained '%s', but was %s", want, s.String())
+		}
+	}
+}
+
+func Test_mockFaults(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := &MockBus{}
+	MockBus_Walk_Faults.OnCall(0, Fault{Err: errors.New("down")})
+	if err := m.Walk(nil); err == nil || err.Error() != "down" {
+		t.Errorf("first call should have failed with down, but was %v", err)
+	}
+	if err := m.Walk(nil); err != nil {
+		t.Errorf("second call should have passed, but was %v", err)
+	}
+}
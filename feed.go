@@ -0,0 +1,119 @@
+package mock
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Feed is the channel a mock method returns when it has no stub, generated mocks hold one per method returning
+// a channel (MockX_Method_Feed). Values sent into it are delivered in order without blocking the sender
+type Feed struct {
+	mu      sync.Mutex
+	ch      reflect.Value
+	queue   []reflect.Value
+	fed     bool
+	closed  bool
+	pumping bool
+	// stop ends the pump of a feed that is reset
+	stop chan struct{}
+}
+
+// NewFeed returns a feed of unbuffered channels of the type of the nil channel typ, e.g. (chan int)(nil)
+func NewFeed(typ interface{}) *Feed {
+	t := reflect.TypeOf(typ)
+	return &Feed{ch: reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.Elem()), 0), stop: make(chan struct{})}
+}
+
+// Chan returns the channel of the feed
+func (f *Feed) Chan() interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ch.Interface()
+}
+
+// Fed tells if values were sent into the feed or it was closed, mocks then return its channel
+func (f *Feed) Fed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fed
+}
+
+// Send queues values for delivery, values sent after Close are dropped
+func (f *Feed) Send(values ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fed = true
+	if f.closed {
+		return
+	}
+	for _, v := range values {
+		val := reflect.ValueOf(v)
+		if !val.IsValid() {
+			val = reflect.Zero(f.ch.Type().Elem())
+		}
+		f.queue = append(f.queue, val)
+	}
+	if !f.pumping && len(f.queue) > 0 {
+		f.pumping = true
+		go f.pump(f.ch, f.stop)
+	}
+}
+
+// Close closes the channel once the queued values are delivered
+func (f *Feed) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fed = true
+	if f.closed {
+		return
+	}
+	f.closed = true
+	if !f.pumping {
+		f.ch.Close()
+	}
+}
+
+// Reset replaces the channel with a new one, values still queued are dropped
+func (f *Feed) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	close(f.stop)
+	f.stop = make(chan struct{})
+	f.ch = reflect.MakeChan(f.ch.Type(), 0)
+	f.queue = nil
+	f.fed = false
+	f.closed = false
+	f.pumping = false
+}
+
+// pump delivers queued values into ch one by one, closing it after the last one when the feed is closed.
+// It ends when stop is closed
+func (f *Feed) pump(ch reflect.Value, stop chan struct{}) {
+	for {
+		f.mu.Lock()
+		select {
+		case <-stop:
+			f.mu.Unlock()
+			return
+		default:
+		}
+		if len(f.queue) == 0 {
+			f.pumping = false
+			if f.closed {
+				ch.Close()
+			}
+			f.mu.Unlock()
+			return
+		}
+		v := f.queue[0]
+		f.queue = f.queue[1:]
+		f.mu.Unlock()
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: ch, Send: v},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+		}
+		if chosen, _, _ := reflect.Select(cases); chosen == 1 {
+			return
+		}
+	}
+}
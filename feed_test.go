@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_feed(t *testing.T) {
+	f := NewFeed((chan int)(nil))
+	if f.Fed() {
+		t.Errorf("new feed should NOT have been fed")
+	}
+	f.Send(1, 2)
+	f.Send(nil)
+	f.Close()
+	f.Send(4)
+	got := make([]int, 0)
+	for v := range f.Chan().(chan int) {
+		got = append(got, v)
+	}
+	if !f.Fed() || len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 0 {
+		t.Errorf("values sent before Close should have been delivered in order, but were %v", got)
+	}
+	f.Reset()
+	f.Send(5)
+	f.Reset()
+	if f.Fed() {
+		t.Errorf("reset feed should NOT have been fed")
+	}
+	f.Send(6)
+	select {
+	case v := <-f.Chan().(chan int):
+		if v != 6 {
+			t.Errorf("value should have been %d, but was %d", 6, v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("value sent after Reset should have been delivered")
+	}
+}
+
+func Test_mockFeed(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := &MockBus{}
+	m.SubscribeSend(Message{Name: "a"}, Message{Name: "b"})
+	m.SubscribeClose()
+	ch, err := m.Subscribe("topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := ""
+	for msg := range ch {
+		names += msg.Name
+	}
+	if names != "ab" {
+		t.Errorf("fed values should have been delivered in order, but were %s", names)
+	}
+}
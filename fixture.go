@@ -0,0 +1,195 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// FixtureCall is one recorded call of a component, arguments and results are JSON encoded.
+// Context arguments are recorded as null and error results as their message
+type FixtureCall struct {
+	Method  string            `json:"method"`
+	Args    []json.RawMessage `json:"args"`
+	Results []json.RawMessage `json:"results"`
+}
+
+// Fixture holds the calls of a real component recorded by a generated recording wrapper (MockXRecording),
+// which a generated mock replays. Fixtures are stored as JSON under testdata
+type Fixture struct {
+	// Path of the fixture file
+	Path  string
+	Calls []FixtureCall
+	mu    sync.Mutex
+	err   error
+	// served counts the replays of each recorded call
+	served map[int]int
+}
+
+// NewFixture returns an empty fixture stored at testdata/<name>.json
+func NewFixture(name string) *Fixture {
+	return &Fixture{Path: filepath.Join("testdata", name+".json")}
+}
+
+// LoadFixture reads the fixture stored at testdata/<name>.json
+func LoadFixture(name string) (*Fixture, error) {
+	f := NewFixture(name)
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &f.Calls); err != nil {
+		return nil, fmt.Errorf("fixture %s: %s", f.Path, err)
+	}
+	for i := range f.Calls {
+		for j, a := range f.Calls[i].Args {
+			f.Calls[i].Args[j] = compact(a)
+		}
+	}
+	return f, nil
+}
+
+// Record appends a call of method, the first encoding error is returned by Save
+func (f *Fixture) Record(method string, args []interface{}, results ...interface{}) {
+	call := FixtureCall{Method: method}
+	var err error
+	if call.Args, err = encode(args); err == nil {
+		call.Results, err = encode(results)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err != nil {
+		if f.err == nil {
+			f.err = fmt.Errorf("recording %s: %s", method, err)
+		}
+		return
+	}
+	f.Calls = append(f.Calls, call)
+}
+
+// Save writes the fixture to its Path, creating the directory if needed
+func (f *Fixture) Save() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	data, err := json.MarshalIndent(f.Calls, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, append(data, '\n'), 0644)
+}
+
+// Replay decodes into results, pointers to the results of the call, the results of the first recorded call of
+// method with the same arguments. Recorded calls are served in order, the last one is repeated once all are served.
+// An error is returned when no call matches
+func (f *Fixture) Replay(method string, args []interface{}, results ...interface{}) error {
+	raw, err := encode(args)
+	if err != nil {
+		return fmt.Errorf("replaying %s: %s", method, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.served == nil {
+		f.served = make(map[int]int)
+	}
+	match := -1
+	for i, c := range f.Calls {
+		if c.Method != method || !sameArgs(c.Args, raw) {
+			continue
+		}
+		match = i
+		if f.served[i] == 0 {
+			break
+		}
+	}
+	if match < 0 {
+		return fmt.Errorf("fixture %s has no call %s(%s)", f.Path, method, joinArgs(raw))
+	}
+	f.served[match]++
+	call := f.Calls[match]
+	if len(call.Results) != len(results) {
+		return fmt.Errorf("fixture %s has %d results for %s, but %d are needed", f.Path, len(call.Results), method, len(results))
+	}
+	for i, r := range results {
+		if err := decode(call.Results[i], r); err != nil {
+			return fmt.Errorf("replaying %s: %s", method, err)
+		}
+	}
+	return nil
+}
+
+// encode encodes values one by one, contexts as null and errors as their message
+func encode(values []interface{}) ([]json.RawMessage, error) {
+	raw := make([]json.RawMessage, 0, len(values))
+	for _, v := range values {
+		switch x := v.(type) {
+		case context.Context:
+			v = nil
+		case error:
+			v = x.Error()
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, data)
+	}
+	return raw, nil
+}
+
+// decode sets the value ptr points to from data, error values are built from their message
+func decode(data json.RawMessage, ptr interface{}) error {
+	v := reflect.ValueOf(ptr).Elem()
+	if v.Type() != errorType {
+		return json.Unmarshal(data, ptr)
+	}
+	var msg *string
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	if msg != nil {
+		v.Set(reflect.ValueOf(errors.New(*msg)))
+	}
+	return nil
+}
+
+func sameArgs(a, b []json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func joinArgs(args []json.RawMessage) []byte {
+	list := make([][]byte, 0, len(args))
+	for _, a := range args {
+		list = append(list, a)
+	}
+	return bytes.Join(list, []byte(", "))
+}
+
+// compact drops the indentation of a value read from a fixture file so it compares with encoded arguments
+func compact(data json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_fixtureReplay(t *testing.T) {
+	f, err := LoadFixture("l1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s string
+	var p *int
+	for _, want := range []string{"first", "second", "second"} {
+		if err := f.Replay("LM1", []interface{}{1, float32(2)}, &s, &p); err != nil || s != want {
+			t.Errorf("result should have been %s, but was %s (%v)", want, s, err)
+		}
+	}
+	if p != nil {
+		t.Errorf("pointer should have been nil, but was %v", *p)
+	}
+	var e error
+	if err := f.Replay("Load", []interface{}{"id"}, &s, &e); err != nil || e == nil || e.Error() != "not found" {
+		t.Errorf("error should have been 'not found', but was %v (%v)", e, err)
+	}
+	err = f.Replay("LM1", []interface{}{5, float32(2)}, &s, &p)
+	if err == nil || !strings.Contains(err.Error(), "no call LM1(5, 2)") {
+		t.Errorf("unmatched call should have failed, but was %v", err)
+	}
+}
+
+func Test_fixtureRecord(t *testing.T) {
+	f := NewFixture("store")
+	f.Path = filepath.Join(t.TempDir(), "testdata", "store.json")
+	f.Record("Fetch", []interface{}{context.Background(), "id"}, "", errors.New("gone"))
+	f.Record("Load", []interface{}{"id"}, "value", nil)
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(f.Path)
+	for _, want := range []string{`"method": "Fetch"`, "null,\n      \"id\"", `"gone"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("fixture should have contained %s, but was %s", want, data)
+		}
+	}
+	var s string
+	var e error
+	if err := f.Replay("Fetch", []interface{}{context.TODO(), "id"}, &s, &e); err != nil || e.Error() != "gone" {
+		t.Errorf("error should have been 'gone', but was %v (%v)", e, err)
+	}
+	f.Record("Watch", []interface{}{make(chan int)})
+	if err := f.Save(); err == nil || !strings.Contains(err.Error(), "recording Watch") {
+		t.Errorf("unencodable argument should have failed Save, but was %v", err)
+	}
+}
+
+func Test_fixture(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	f, err := LoadFixture("l1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := (MockL1{Fixture: f}).LM1(1, 2); s != "first" {
+		t.Errorf("unstubbed call should have been replayed from the fixture, but returned %s", s)
+	}
+	rec := &MockL1Recording{Real: &L1{}, Fixture: NewFixture("recorded")}
+	if s, _ := rec.LM1(1, 2); s != "return from LM1" {
+		t.Errorf("recording should have called the real component, but returned %s", s)
+	}
+	if s, p := (MockL1{Fixture: rec.Fixture}).LM1(1, 2); s != "return from LM1" || p == nil || *p != 100 {
+		t.Errorf("recorded call should have been replayed, but returned %s %v", s, p)
+	}
+}
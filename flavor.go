@@ -0,0 +1,192 @@
+package mock
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Flavor selects the style of generated mocks, each flavor overrides blocks of the default template
+type Flavor string
+
+const (
+	// Classic mocks delegate to package level stubs and record calls with NumCalls and CallParams, the default
+	Classic Flavor = "classic"
+	// Testify mocks embed testify's mock.Mock and are stubbed with On(...).Return(...)
+	Testify Flavor = "testify"
+	// Gomock mocks are driven by a gomock.Controller and stubbed with EXPECT()
+	Gomock Flavor = "gomock"
+)
+
+// flavors holds the block overrides of each flavor
+var flavors = map[Flavor]string{
+	Classic: "",
+	Testify: testifyTemplate,
+	Gomock:  gomockTemplate,
+}
+
+// WithFlavor sets the flavor of all generated mocks
+func WithFlavor(f Flavor) Option {
+	return func(b *builder) {
+		b.Flavor = f
+	}
+}
+
+// WithComponentFlavor sets the flavor of the file generated for a component, overriding WithFlavor. Mocks of a
+// package share their recorder, so components generated into the same package must have the same flavor
+func WithComponentFlavor(component string, f Flavor) Option {
+	return func(b *builder) {
+		b.Flavors[component] = f
+	}
+}
+
+// flavor returns the flavor of the file generated for a component
+func (b *builder) flavor(component string) Flavor {
+	if f, ok := b.Flavors[component]; ok {
+		return f
+	}
+	return b.Flavor
+}
+
+// imports are the packages the blocks of a flavor refer to in a file. Testify and gomock mocks declare the method
+// signatures only, classic mocks the fields of the mocked structs as well
+func (f Flavor) imports(file *File) []string {
+	switch f {
+	case Testify:
+		return append([]string{"github.com/stretchr/testify/mock"}, file.sigs...)
+	case Gomock:
+		imports := append([]string{"go.uber.org/mock/gomock"}, file.sigs...)
+		for _, m := range file.Mocks {
+			if len(m.Methods) > 0 {
+				// the recorders of methods take the method types
+				return append(imports, "reflect")
+			}
+		}
+		return imports
+	}
+	imports := append([]string{}, file.types...)
+	if file.Runtime != "" {
+		imports = append(imports, file.RuntimePath)
+	}
+	if file.Shared {
+		imports = append(imports, "fmt", "reflect")
+	}
+	if file.Shared && file.Record {
+		imports = append(imports, "flag", "runtime", "strconv", "strings", "sync", "testing", "time")
+	}
+	return imports
+}
+
+// checkFlavors fails when components generated into the same package have different flavors
+func (b *builder) checkFlavors(paths []string, files map[string][]*genInfo) error {
+	first := make(map[string]string)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		for _, c := range files[path] {
+			name := c.EnclosingType.Name
+			other, ok := first[dir]
+			if !ok {
+				first[dir] = name
+			} else if b.flavor(other) != b.flavor(name) {
+				return fmt.Errorf("flavor %s of %s differs from flavor %s of %s in package %s, flavors are set per package",
+					b.flavor(name), name, b.flavor(other), other, dir)
+			}
+		}
+	}
+	return nil
+}
+
+const testifyTemplate = `
+{{define "header"}}
+package {{.Package}}
+import (
+{{range .Imports}}"{{.}}"
+{{end}})
+{{end}}
+
+{{define "recorder"}}
+// Calls are recorded by the embedded mock.Mock
+{{end}}
+
+{{define "defaults"}}
+// Results of methods without expectations are decided by mock.Mock
+{{end}}
+
+{{define "mock"}}
+// Begin of mock for {{.Struct}} and its methods
+type {{.Name}} struct {
+	mock.Mock
+}
+{{range .Methods}}{{template "method" .}}{{end}}
+// End of mock for {{.Struct}} and its methods
+{{end}}
+
+{{define "method"}}
+func (m *{{.Mock.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	{{if .Out}}args := {{end}}m.Called({{.Names}})
+	{{range $i, $o := .Out}}var r{{$i}} {{$o.Type}}
+	if v := args.Get({{$i}}); v != nil {
+		r{{$i}} = v.({{$o.Type}})
+	}
+	{{end}}{{if .Out}}return {{range $i, $o := .Out}}{{if $i}}, {{end}}r{{$i}}{{end}}{{end}}
+}
+{{end}}
+`
+
+const gomockTemplate = `
+{{define "header"}}
+package {{.Package}}
+import (
+{{range .Imports}}"{{.}}"
+{{end}})
+{{end}}
+
+{{define "recorder"}}
+// Calls are recorded by gomock.Controller
+{{end}}
+
+{{define "defaults"}}
+// Unexpected calls are reported by gomock.Controller
+{{end}}
+
+{{define "mock"}}
+// {{.Name}} is a mock of {{.Struct}}
+type {{.Name}} struct {
+	ctrl     *gomock.Controller
+	recorder *{{.Name}}MockRecorder
+}
+
+// {{.Name}}MockRecorder is the mock recorder for {{.Name}}
+type {{.Name}}MockRecorder struct {
+	mock *{{.Name}}
+}
+
+// New{{.Name}} creates a new mock instance
+func New{{.Name}}(ctrl *gomock.Controller) *{{.Name}} {
+	mock := &{{.Name}}{ctrl: ctrl}
+	mock.recorder = &{{.Name}}MockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *{{.Name}}) EXPECT() *{{.Name}}MockRecorder {
+	return m.recorder
+}
+{{range .Methods}}{{template "method" .}}{{end}}
+{{end}}
+
+{{define "method"}}
+// {{.Name}} mocks base method
+func (m *{{.Mock.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	m.ctrl.T.Helper()
+	{{if .Out}}ret := {{end}}m.ctrl.Call(m, "{{.Name}}"{{range .In}}, {{.Name}}{{end}})
+	{{range $i, $o := .Out}}ret{{$i}}, _ := ret[{{$i}}].({{$o.Type}})
+	{{end}}{{if .Out}}return {{range $i, $o := .Out}}{{if $i}}, {{end}}ret{{$i}}{{end}}{{end}}
+}
+
+// {{.Name}} indicates an expected call of {{.Name}}
+func (mr *{{.Mock.Name}}MockRecorder) {{.Name}}({{range $i, $p := .In}}{{if $i}}, {{end}}{{$p.Name}}{{end}}{{if .In}} interface{}{{end}}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "{{.Name}}", reflect.TypeOf((*{{.Mock.Name}})(nil).{{.Name}}){{range .In}}, {{.Name}}{{end}})
+}
+{{end}}
+`
@@ -0,0 +1,109 @@
+package mock
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func Test_testify(t *testing.T) {
+	files := generate(t, WithFlavor(Testify), WithFileName("mock_%s_test.go"))
+	s := files["mock_l1_test.go"]
+	for _, want := range []string{
+		"\"github.com/stretchr/testify/mock\"",
+		"type MockL1 struct {\n\tmock.Mock\n}",
+		"func (m *MockL1) LM1(a1 int, a2 float32) (string, *int) {\n\targs := m.Called(a1, a2)",
+		"\tvar r1 *int\n\tif v := args.Get(1); v != nil {\n\t\tr1 = v.(*int)\n\t}\n\treturn r0, r1\n",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("should have contained '%s', but was %s", want, s)
+		}
+	}
+	if want := "func (m *MockL2) LM21(a1 int, a2 float32) string {"; !strings.Contains(files["mock_l2_test.go"], want) {
+		t.Errorf("should have contained '%s', but was %s", want, files["mock_l2_test.go"])
+	}
+	if strings.Contains(s, "func NumCalls") {
+		t.Errorf("recorder should NOT have been emitted")
+	}
+	// the time field of L2 is not part of the testify mock
+	checkImports(t, files["mock_l1_test.go"], "github.com/stretchr/testify/mock", "time")
+	checkImports(t, files["mock_l2_test.go"], "github.com/stretchr/testify/mock")
+}
+
+// checkImports fails the test unless src imports exactly the packages want, in order
+func checkImports(t *testing.T, src string, want ...string) {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("should have parsed, but got %v", err)
+	}
+	imports := make([]string, 0)
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		imports = append(imports, path)
+	}
+	if !reflect.DeepEqual(imports, want) {
+		t.Errorf("imports should have been %v, but were %v", want, imports)
+	}
+}
+
+func Test_componentFlavor(t *testing.T) {
+	files := generate(t, WithComponentFlavor("L1", Testify), WithComponentFlavor("L2", Testify), WithFileName("mock_%s_test.go"))
+	if !strings.Contains(files["mock_l1_test.go"], "mock.Mock") || !strings.Contains(files["mock_l2_test.go"], "mock.Mock") {
+		t.Errorf("L1 and L2 should have been generated in the testify flavor")
+	}
+	m := New("mock", WithComponentFlavor("L2", Testify), WithFileName("mock_%s_test.go"), WithReporter(Silent))
+	m.Register([]fuse.Entry{{Name: "L1", Instance: &L1{}}, {Name: "L2", Instance: &L2{}}})
+	if errs := m.Generate(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "flavors are set per package") {
+		t.Errorf("mixed flavors in a package should have errored out, but got %v", errs)
+	}
+	m = New("mock", WithFlavor("unknown"), WithReporter(Silent))
+	if errs := m.Generate(); len(errs) != 1 {
+		t.Errorf("unknown flavor should have errored out")
+	}
+}
+
+func Test_gomock(t *testing.T) {
+	files := generate(t, WithFlavor(Gomock), WithFileName("mock_%s_test.go"))
+	s := files["mock_l1_test.go"]
+	for _, want := range []string{
+		"\"go.uber.org/mock/gomock\"",
+		"type MockL1 struct {\n\tctrl     *gomock.Controller\n\trecorder *MockL1MockRecorder\n}",
+		"func NewMockL1(ctrl *gomock.Controller) *MockL1 {",
+		"func (m *MockL1) EXPECT() *MockL1MockRecorder {",
+		"\tret := m.ctrl.Call(m, \"LM1\", a1, a2)\n\tret0, _ := ret[0].(string)\n\tret1, _ := ret[1].(*int)\n\treturn ret0, ret1\n",
+		"func (mr *MockL1MockRecorder) LM1(a1, a2 interface{}) *gomock.Call {",
+		"reflect.TypeOf((*MockL1)(nil).LM1), a1, a2)",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("should have contained '%s', but was %s", want, s)
+		}
+	}
+	if want := "func (mr *MockL2MockRecorder) LM21(a1, a2 interface{}) *gomock.Call {"; !strings.Contains(files["mock_l2_test.go"], want) {
+		t.Errorf("should have contained '%s', but was %s", want, files["mock_l2_test.go"])
+	}
+	checkImports(t, s, "go.uber.org/mock/gomock", "reflect", "time")
+	checkImports(t, files["mock_l2_test.go"], "go.uber.org/mock/gomock", "reflect")
+	// the recorder of a mock without methods does not refer to reflect
+	files = make(map[string]string)
+	m := New("mock", WithFlavor(Gomock), WithReporter(Silent), WithOutput(func(path string, src []byte) error {
+		files[filepath.Base(path)] = string(src)
+		return nil
+	}))
+	m.Register([]fuse.Entry{{Name: "Svc3", Instance: &Svc3{}}})
+	if errs := m.Generate(); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	for _, src := range files {
+		checkImports(t, src, "go.uber.org/mock/gomock")
+	}
+	if len(files) != 1 {
+		t.Errorf("number of files should have been %d, but was %d", 1, len(files))
+	}
+}
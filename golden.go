@@ -0,0 +1,152 @@
+package mock
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/tabwriter"
+)
+
+// LoggedCall is a call of a mock method as written to a golden call log
+type LoggedCall struct {
+	// Name is the key of the method, e.g. MockL1_LM1
+	Name    string
+	Args    []interface{}
+	Results []interface{}
+	// Panic is the value the call panicked with
+	Panic interface{}
+	// Caller is the file:line the mock was called from, it is left out of call logs
+	Caller string
+}
+
+// CallLog renders calls one per line, e.g. MockL1_LM1(1, 2) -> ("a", null). Values are JSON encoded, contexts
+// as null and errors as their message, so the log is stable across runs
+func CallLog(calls []LoggedCall) string {
+	var b strings.Builder
+	for _, c := range calls {
+		fmt.Fprintf(&b, "%s(%s)", c.Name, logValues(c.Args))
+		if c.Panic != nil {
+			fmt.Fprintf(&b, " panic %s\n", logValues([]interface{}{c.Panic}))
+			continue
+		}
+		if len(c.Results) > 0 {
+			fmt.Fprintf(&b, " -> (%s)", logValues(c.Results))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// CallTable renders calls as a table per mock, in the order of the first call of each mock. Calls are numbered
+// in the order they were made across mocks
+func CallTable(calls []LoggedCall) string {
+	mocks := make([]string, 0)
+	rows := make(map[string][]string)
+	for i, c := range calls {
+		mock, method := c.Name, c.Name
+		if n := strings.LastIndex(c.Name, "_"); n > 0 {
+			mock, method = c.Name[:n], c.Name[n+1:]
+		}
+		if _, ok := rows[mock]; !ok {
+			mocks = append(mocks, mock)
+		}
+		results := logValues(c.Results)
+		if c.Panic != nil {
+			results = "panic " + logValues([]interface{}{c.Panic})
+		}
+		row := fmt.Sprintf("  %d\t%s\t%s\t%s\t%s", i, method, logValues(c.Args), results, filepath.Base(c.Caller))
+		rows[mock] = append(rows[mock], row)
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for _, mock := range mocks {
+		fmt.Fprintf(w, "%s\n  #\tMETHOD\tARGS\tRESULTS\tCALLER\n", mock)
+		for _, row := range rows[mock] {
+			fmt.Fprintln(w, row)
+		}
+	}
+	w.Flush()
+	return b.String()
+}
+
+// logValues joins the encoded values, values without a JSON encoding (e.g. channels, complex numbers) are printed with %v
+func logValues(values []interface{}) string {
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		raw, err := encode([]interface{}{v})
+		if err != nil {
+			list = append(list, fmt.Sprintf("%v", v))
+			continue
+		}
+		list = append(list, string(raw[0]))
+	}
+	return strings.Join(list, ", ")
+}
+
+// AssertGolden compares got with the golden file testdata/<name>.golden and reports a line diff on mismatch.
+// When the tests run with -update, the golden file is rewritten instead. The flag is registered by generated
+// mocks unless the test package defines it
+func AssertGolden(t testing.TB, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if f := flag.Lookup("update"); f != nil && f.Value.String() == "true" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("golden file %s should have been readable, run the tests with -update to create it: %s", path, err)
+		return
+	}
+	if string(want) != got {
+		t.Errorf("call log should have matched %s (-want +got):\n%s", path, diff(string(want), got))
+	}
+}
+
+// diff returns the lines of want and got that differ, marked with - and + around their longest common subsequence
+func diff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return sb.String()
+}
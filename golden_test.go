@@ -0,0 +1,130 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_callLog(t *testing.T) {
+	log := CallLog([]LoggedCall{
+		{Name: "MockL1_LM1", Args: []interface{}{1, float32(2.5)}, Results: []interface{}{"a", nil}},
+		{Name: "MockStore_Fetch", Args: []interface{}{context.Background(), "id"}, Results: []interface{}{"", errors.New("gone")}},
+		{Name: "MockSvc1_M1", Args: []interface{}{complex(1, 2)}},
+		{Name: "MockL1_LM3", Args: []interface{}{nil}, Panic: "boom"},
+	})
+	want := "MockL1_LM1(1, 2.5) -> (\"a\", null)\n" +
+		"MockStore_Fetch(null, \"id\") -> (\"\", \"gone\")\n" +
+		"MockSvc1_M1((1+2i))\n" +
+		"MockL1_LM3(null) panic \"boom\"\n"
+	if log != want {
+		t.Errorf("call log should have been\n%s, but was\n%s", want, log)
+	}
+}
+
+func Test_diff(t *testing.T) {
+	d := diff("a\nb\nc\n", "a\nx\nc\nd\n")
+	want := "  a\n- b\n+ x\n  c\n+ d\n"
+	if d != want {
+		t.Errorf("diff should have been\n%s, but was\n%s", want, d)
+	}
+}
+
+func Test_assertGolden(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "rewrite golden files")
+	}
+	defer flag.Set("update", "false")
+	flag.Set("update", "true")
+	AssertGolden(t, "calls", "MockL1_LM1(1, 2)\n")
+	data, err := ioutil.ReadFile("testdata/calls.golden")
+	if err != nil || string(data) != "MockL1_LM1(1, 2)\n" {
+		t.Errorf("golden file should have been written, but was %s (%v)", data, err)
+	}
+	flag.Set("update", "false")
+	AssertGolden(t, "calls", "MockL1_LM1(1, 2)\n")
+}
+
+func Test_callTable(t *testing.T) {
+	table := CallTable([]LoggedCall{
+		{Name: "MockL1_LM1", Args: []interface{}{1, float32(2)}, Results: []interface{}{"a", nil}, Caller: "/src/x_test.go:12"},
+		{Name: "MockL2_LM21", Args: []interface{}{3, float32(1.5)}, Results: []interface{}{""}, Caller: "/src/x_test.go:13"},
+		{Name: "MockL1_LM3", Args: []interface{}{nil}, Panic: errors.New("boom"), Caller: "/src/x_test.go:14"},
+	})
+	want := "MockL1\n" +
+		"  #  METHOD  ARGS  RESULTS       CALLER\n" +
+		"  0  LM1     1, 2  \"a\", null     x_test.go:12\n" +
+		"  2  LM3     null  panic \"boom\"  x_test.go:14\n" +
+		"MockL2\n" +
+		"  #  METHOD  ARGS    RESULTS  CALLER\n" +
+		"  1  LM21    3, 1.5  \"\"       x_test.go:13\n"
+	if table != want {
+		t.Errorf("call table should have been\n%s, but was\n%s", want, table)
+	}
+}
+
+func Test_callLogPerTest(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	os.Mkdir("testdata", 0755)
+	ioutil.WriteFile("testdata/first.golden", []byte("MockL1_LM1(1, 2) -> (\"\", null)\n"), 0644)
+	ioutil.WriteFile("testdata/second.golden", []byte("MockL2_LM21(3, 1.5) -> (\"\")\n"), 0644)
+	t.Cleanup(ResetMocks)
+	MockL2{}.LM21(0, 0)
+	t.Run("first", func(t *testing.T) {
+		Attach(t)
+		MockL1{}.LM1(1, 2)
+		AssertCallLog(t, "first")
+	})
+	t.Run("second", func(t *testing.T) {
+		Attach(t)
+		MockL2{}.LM21(3, 1.5)
+		AssertCallLog(t, "second")
+	})
+}
+
+// failedTB is a failed test collecting its logs and cleanups
+type failedTB struct {
+	testing.TB
+	logs     []string
+	cleanups []func()
+}
+
+func (f *failedTB) Name() string      { return "failed" }
+func (f *failedTB) Failed() bool      { return true }
+func (f *failedTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *failedTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func Test_attachMark(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	MockL2{}.LM21(0, 0)
+	tb := &failedTB{TB: t}
+	Attach(tb)
+	MockL1{}.LM1(1, 2)
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	if len(tb.logs) != 1 || !strings.Contains(tb.logs[0], "LM1") || strings.Contains(tb.logs[0], "LM21") {
+		t.Errorf("only the calls after Attach should have been logged, but were %v", tb.logs)
+	}
+	if NumCalls("MockL1_LM1") != 1 || NumCalls("MockL2_LM21") != 1 {
+		t.Errorf("the calls should have been kept after the test")
+	}
+}
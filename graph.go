@@ -0,0 +1,167 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Graph is the dependency graph of registered components, keyed by component name
+type Graph struct {
+	// Nodes are the names of all registered components, sorted
+	Nodes []string
+	// Edges maps a component to the components it depends on
+	Edges map[string][]string
+}
+
+// Graph builds the dependency graph from the `_fuse` fields, embedded interfaces and `_deps` tags of registered
+// components
+func (b *builder) Graph() *Graph {
+	g := &Graph{Edges: make(map[string][]string)}
+	for name := range b.Registry {
+		g.Nodes = append(g.Nodes, name)
+	}
+	sort.Strings(g.Nodes)
+	for _, name := range g.Nodes {
+		c := b.Registry[name]
+		el := reflect.TypeOf(c.Instance).Elem()
+		for i := 0; i < el.NumField(); i++ {
+			f := el.Field(i)
+			if dependency(f) {
+				if dep := b.resolve(name, f.Tag.Get("_fuse"), f.Type); dep != "" {
+					g.addEdge(name, dep)
+				}
+			}
+			for _, dep := range findDeps(&fieldInfo{StructField: f}) {
+				if _, ok := b.Registry[dep]; ok {
+					g.addEdge(name, dep)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// resolve finds the component a `_fuse` field or embedded interface of component from refers to, first by name
+// and then by type. A component does not resolve to itself by type, e.g. through the methods it embeds
+func (b *builder) resolve(from, name string, t reflect.Type) string {
+	if _, ok := b.Registry[name]; ok {
+		return name
+	}
+	names := make([]string, 0)
+	for n := range b.Registry {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if n != from && reflect.TypeOf(b.Registry[n].Instance).AssignableTo(t) {
+			return n
+		}
+	}
+	return ""
+}
+
+func (g *Graph) addEdge(from, to string) {
+	for _, e := range g.Edges[from] {
+		if e == to {
+			return
+		}
+	}
+	g.Edges[from] = append(g.Edges[from], to)
+	sort.Strings(g.Edges[from])
+}
+
+// Reach returns the transitive dependencies of a component up to depth levels, a negative depth has no limit
+func (g *Graph) Reach(name string, depth int) []string {
+	seen := map[string]bool{name: true}
+	deps := make([]string, 0)
+	level := []string{name}
+	for d := 0; len(level) > 0 && (depth < 0 || d < depth); d++ {
+		next := make([]string, 0)
+		for _, n := range level {
+			for _, e := range g.Edges[n] {
+				if seen[e] {
+					continue
+				}
+				seen[e] = true
+				deps = append(deps, e)
+				next = append(next, e)
+			}
+		}
+		level = next
+	}
+	return deps
+}
+
+// Cycles returns every elementary dependency cycle once, starting and ending with the first of its components in
+// name order
+func (g *Graph) Cycles() [][]string {
+	cycles := make([][]string, 0)
+	for i, start := range g.Nodes {
+		// cycles through components before start were found from them, components on the path are not revisited
+		blocked := make(map[string]bool)
+		for _, n := range g.Nodes[:i] {
+			blocked[n] = true
+		}
+		path := []string{start}
+		var visit func(n string)
+		visit = func(n string) {
+			for _, e := range g.Edges[n] {
+				switch {
+				case e == start:
+					cycles = append(cycles, append(append([]string{}, path...), start))
+				case !blocked[e]:
+					blocked[e] = true
+					path = append(path, e)
+					visit(e)
+					path = path[:len(path)-1]
+					blocked[e] = false
+				}
+			}
+		}
+		visit(start)
+	}
+	return cycles
+}
+
+// DOT renders the graph in Graphviz DOT format
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph components {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\t%q;\n", n)
+	}
+	for _, n := range g.Nodes {
+		for _, e := range g.Edges[n] {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", n, e)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "    %s[%s]\n", mermaidID(n), n)
+	}
+	for _, n := range g.Nodes {
+		for _, e := range g.Edges[n] {
+			fmt.Fprintf(&b, "    %s --> %s\n", mermaidID(n), mermaidID(e))
+		}
+	}
+	return b.String()
+}
+
+// mermaidID strips characters Mermaid does not accept in node ids
+func mermaidID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func graphBuilder(t *testing.T) *builder {
+	m := New("mock")
+	entries := make([]fuse.Entry, 0)
+	entries = append(entries, fuse.Entry{Name: "cyc1", Instance: &Cyc1{}})
+	entries = append(entries, fuse.Entry{Name: "cyc2", Instance: &Cyc2{}})
+	entries = append(entries, fuse.Entry{Name: "cyc3", Instance: &Cyc3{}})
+	entries = append(entries, fuse.Entry{Name: "l1", Instance: &L1{}})
+	entries = append(entries, fuse.Entry{Name: "l2", Instance: &L2{}})
+	entries = append(entries, fuse.Entry{Name: "l3", Instance: &L3{}})
+	if errs := m.Register(entries); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	return m.(*builder)
+}
+
+func Test_graph(t *testing.T) {
+	g := graphBuilder(t).Graph()
+	if len(g.Nodes) != 6 {
+		t.Errorf("number of nodes should have been %d, but was %d", 6, len(g.Nodes))
+	}
+	if !reflect.DeepEqual(g.Edges["l1"], []string{"l2"}) {
+		t.Errorf("l1 should have depended on %v, but was %v", []string{"l2"}, g.Edges["l1"])
+	}
+	if !reflect.DeepEqual(g.Edges["cyc3"], []string{"cyc1", "l3"}) {
+		t.Errorf("cyc3 should have depended on %v, but was %v", []string{"cyc1", "l3"}, g.Edges["cyc3"])
+	}
+}
+
+func Test_reach(t *testing.T) {
+	g := graphBuilder(t).Graph()
+	deps := g.Reach("cyc1", 1)
+	if !reflect.DeepEqual(deps, []string{"cyc2"}) {
+		t.Errorf("should have been %v, but was %v", []string{"cyc2"}, deps)
+	}
+	deps = g.Reach("cyc1", 2)
+	if !reflect.DeepEqual(deps, []string{"cyc2", "cyc3"}) {
+		t.Errorf("should have been %v, but was %v", []string{"cyc2", "cyc3"}, deps)
+	}
+	deps = g.Reach("cyc1", -1)
+	if !reflect.DeepEqual(deps, []string{"cyc2", "cyc3", "l3"}) {
+		t.Errorf("should have been %v, but was %v", []string{"cyc2", "cyc3", "l3"}, deps)
+	}
+}
+
+func Test_cycles(t *testing.T) {
+	g := graphBuilder(t).Graph()
+	cycles := g.Cycles()
+	if len(cycles) != 1 {
+		t.Fatalf("number of cycles should have been %d, but was %d", 1, len(cycles))
+	}
+	if !reflect.DeepEqual(cycles[0], []string{"cyc1", "cyc2", "cyc3", "cyc1"}) {
+		t.Errorf("cycle should have been %v, but was %v", []string{"cyc1", "cyc2", "cyc3", "cyc1"}, cycles[0])
+	}
+}
+
+func Test_allCycles(t *testing.T) {
+	// c closes a second cycle through b after a -> b -> a was found
+	g := &Graph{Nodes: []string{"a", "b", "c"}, Edges: map[string][]string{"a": {"b", "c"}, "b": {"a"}, "c": {"b"}}}
+	want := [][]string{{"a", "b", "a"}, {"a", "c", "b", "a"}}
+	if cycles := g.Cycles(); !reflect.DeepEqual(cycles, want) {
+		t.Errorf("cycles should have been %v, but were %v", want, cycles)
+	}
+}
+
+func Test_depth(t *testing.T) {
+	b := graphBuilder(t)
+	b.Reporter = Silent
+	b.Output = func(string, []byte) error { return nil }
+	b.Generate()
+	l1 := reflect.TypeOf(L1{})
+	cyc1 := reflect.TypeOf(Cyc1{})
+	for _, c := range []struct {
+		depth int
+		t     reflect.Type
+		want  int
+	}{{0, l1, 1}, {1, l1, 2}, {0, cyc1, 1}, {1, cyc1, 2}, {2, cyc1, 3}, {-1, cyc1, 4}} {
+		b.SetDepth(c.depth)
+		if n := len(b.enclosed(c.t, b.infos[c.t], b.Graph()).EnclosedTypes); n != c.want {
+			t.Errorf("depth %d should have mocked %d components for %s, but mocked %d", c.depth, c.want, c.t, n)
+		}
+	}
+}
+
+func Test_render(t *testing.T) {
+	g := graphBuilder(t).Graph()
+	s := g.DOT()
+	if !strings.Contains(s, "\t\"l1\" -> \"l2\";\n") {
+		t.Errorf("DOT output should have contained edge l1 -> l2, but was %s", s)
+	}
+	s = g.Mermaid()
+	if !strings.Contains(s, "    cyc3 --> l3\n") {
+		t.Errorf("Mermaid output should have contained edge cyc3 --> l3, but was %s", s)
+	}
+}
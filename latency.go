@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ContextCall is what a call of a mock method saw of its context.Context
+type ContextCall struct {
+	Deadline    time.Time
+	HasDeadline bool
+	// Values holds the values of the keys set with Latency.Record
+	Values map[interface{}]interface{}
+	// Err is the error of the context once the latency passed or the context was done
+	Err error
+}
+
+// Latency simulates the latency of a mock method taking a context.Context, generated mocks hold one per such
+// method (MockX_Method_Latency). The zero value has no latency and is ready to use
+type Latency struct {
+	mu    sync.Mutex
+	delay time.Duration
+	keys  []interface{}
+	calls []ContextCall
+}
+
+// Set sets the simulated latency of every call
+func (l *Latency) Set(d time.Duration) *Latency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.delay = d
+	return l
+}
+
+// Record sets the context value keys recorded for every call
+func (l *Latency) Record(keys ...interface{}) *Latency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.keys = append(l.keys, keys...)
+	return l
+}
+
+// Calls returns what the calls so far saw of their contexts
+func (l *Latency) Calls() []ContextCall {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]ContextCall{}, l.calls...)
+}
+
+// Reset removes the latency and the recorded calls
+func (l *Latency) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.delay = 0
+	l.keys = nil
+	l.calls = nil
+}
+
+// Wait waits for the latency and returns ctx.Err() when the context is done first, a context that is already done
+// fails without waiting. It returns at once when neither a latency nor keys are set, a nil ctx has no deadline
+func (l *Latency) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	delay, keys := l.delay, l.keys
+	l.mu.Unlock()
+	if delay == 0 && len(keys) == 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	call := ContextCall{Values: make(map[interface{}]interface{})}
+	call.Deadline, call.HasDeadline = ctx.Deadline()
+	for _, k := range keys {
+		call.Values[k] = ctx.Value(k)
+	}
+	err := ctx.Err()
+	if err == nil && delay > 0 {
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			err = ctx.Err()
+		case <-t.C:
+		}
+	}
+	call.Err = err
+	l.mu.Lock()
+	l.calls = append(l.calls, call)
+	l.mu.Unlock()
+	return err
+}
@@ -0,0 +1,81 @@
+package mock
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ctxKey string
+
+func Test_latency(t *testing.T) {
+	l := (&Latency{}).Set(time.Hour).Record(ctxKey("user"))
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey("user"), "bob"))
+	cancel()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("cancelled context should have failed with %v, but was %v", context.Canceled, err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("context timing out during latency should have failed with %v, but was %v", context.DeadlineExceeded, err)
+	}
+	calls := l.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("number of calls should have been %d, but was %d", 2, len(calls))
+	}
+	if calls[0].Values[ctxKey("user")] != "bob" || calls[0].HasDeadline {
+		t.Errorf("first call should have seen user bob and no deadline, but was %+v", calls[0])
+	}
+	if !calls[1].HasDeadline || calls[1].Err != context.DeadlineExceeded {
+		t.Errorf("second call should have seen a deadline, but was %+v", calls[1])
+	}
+	l.Reset()
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err != nil || len(l.Calls()) != 0 {
+		t.Errorf("reset latency should have passed immediately without recording, but was %v", err)
+	}
+}
+
+func Test_latencyContext(t *testing.T) {
+	b := New("mock").(*builder)
+	info := populateInfo(Component{Name: "Store", Instance: &Store{}})
+	info.MockName = "MockStore"
+	ginfo := genInfo{EnclosingType: info, EnclosedTypes: map[reflect.Type]*typeInfo{info.Typ: info}, Record: true, Shared: true}
+	f := b.fileData(&ginfo)
+	var fetch *Method
+	for _, m := range f.Mocks[0].Methods {
+		if m.Name == "Fetch" {
+			fetch = m
+		}
+	}
+	if fetch == nil || fetch.Ctx != "a1" || !fetch.In[0].Context {
+		t.Fatalf("a1 should have been the context of Fetch, but was %+v", fetch)
+	}
+}
+
+func Test_mockLatency(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	MockClock_FakeFetch_Latency.Set(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := NewMockClock().Fetch(ctx, "id"); err != context.DeadlineExceeded {
+		t.Errorf("call should have failed with %v, but was %v", context.DeadlineExceeded, err)
+	}
+}
+
+func Test_nilContext(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := NewMockClock()
+	m.FakeFetch(nil, "x")
+	MockClock_FakeFetch_Latency.Set(time.Millisecond).Record(ctxKey("user"))
+	if _, err := m.FakeFetch(nil, "x"); err != nil {
+		t.Errorf("nil context should have had no deadline, but failed with %v", err)
+	}
+	if calls := MockClock_FakeFetch_Latency.Calls(); len(calls) != 1 || calls[0].HasDeadline || calls[0].Values[ctxKey("user")] != nil {
+		t.Errorf("call with a nil context should have been recorded without deadline or values, but were %+v", calls)
+	}
+	MockClock_FakeFetch_Reset()
+}
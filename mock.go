@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"go/format"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -16,15 +19,19 @@ import (
 )
 
 type Mock interface {
-	// Register a slice of components
+	// Register a slice of components, guarded by the builder's Policy
 	Register(entries []fuse.Entry) []error
 	// Generate mocks
 	Generate() []error
+	// Graph returns the dependency graph of registered components
+	Graph() *Graph
+	// SetDepth sets how many levels of dependencies are mocked alongside a component, negative for all levels and
+	// 0 for none
+	SetDepth(depth int)
+	// Swap replaces the named components of entries by their generated mocks, guarded by the builder's Policy
+	Swap(entries []fuse.Entry, names ...string) ([]fuse.Entry, []error)
 }
 
-// bpath is the base path at which all mocks are generated
-var bpath string
-
 type Component struct {
 	// Component key, required
 	Name string
@@ -32,7 +39,7 @@ type Component struct {
 	Instance interface{}
 	// Stateless of stateful
 	//Stateless bool
-	//Base path to generate mocks
+	// Basepath is the source directory of the component, mocks are generated into it
 	Basepath string
 }
 
@@ -42,6 +49,8 @@ type param struct {
 	Name   string
 	Ptr    bool
 	InName string
+	// Variadic is set for the last input of a variadic method, Typ is then the slice type
+	Variadic bool
 }
 
 type typeInfo struct {
@@ -51,17 +60,29 @@ type typeInfo struct {
 	Name       string
 	Basepath   string
 	StructName string
+	MockName   string
 	PkgPath    string
 	PkgString  string
 	Pkg        string
 	Funcs      []*funcInfo
 	Fields     []*fieldInfo
 	Deps       []reflect.Type
+	// Names are the names the struct is registered under, Name first
+	Names []string
+	// Unfaked are the tagged func fields without a fake, variadic funcs are not faked
+	Unfaked []string
 }
 
 type genInfo struct {
 	EnclosingType *typeInfo
 	EnclosedTypes map[reflect.Type]*typeInfo
+	Record        bool
+	// Path is the file generated
+	Path string
+	// Shared is set on the first file of a package, which declares what the mocks of the package share
+	Shared bool
+	// Funcs are the stub func types declared in the package so far, by name with their signature
+	Funcs map[string]string
 }
 
 type fieldInfo struct {
@@ -71,59 +92,108 @@ type fieldInfo struct {
 	StructField reflect.StructField
 }
 
-var mockInfoMap = make(map[reflect.Type]*typeInfo, 0)
-
 type funcInfo struct {
 	Name   string
 	Params []*param
+	// Field is the func field a fake stands in for, blank for methods
+	Field string
 }
 
-var funcMap template.FuncMap = make(map[string]interface{}, 0)
-
 type builder struct {
 	Registry map[string]Component
-	Errors   []error
-	Basepath string
+	// infos holds the type information of registered components during Generate
+	infos  map[reflect.Type]*typeInfo
+	Errors []error
+	Depth  int
+	Policy Policy
+	// ModuleDir is where the module containing the components is looked up
+	ModuleDir string
+	module    *module
+	Reporter  Reporter
+	Output    Sink
+	FileName  string
+	Prefix    string
+	Suffix    string
+	Recorder  Recorder
+	Format    bool
+	Flavor    Flavor
+	Flavors   map[string]Flavor
+	// Spies are the components mocked as spies, all components when it holds ""
+	Spies map[string]bool
+	// Fixtures are the components mocked with record and replay support, all components when it holds ""
+	Fixtures map[string]bool
+	// Templates override the default template or its blocks
+	Templates  []tmplSource
+	tmplErrors []error
 }
 
-// New initializes the builder for mocks
-func New(basepath string) Mock {
+// New initializes the builder for mocks. Mocks are written into the source directory of each component, so basepath
+// is ignored, it is kept for compatibility
+func New(basepath string, opts ...Option) Mock {
 	b := builder{}
-	b.init(basepath)
-	bpath = basepath
+	b.init()
+	for _, opt := range opts {
+		opt(&b)
+	}
 	return &b
 }
 
-func (b *builder) init(basepath string) {
+func (b *builder) init() {
 	b.Registry = make(map[string]Component)
-	b.Basepath = basepath
+	b.Depth = 1
+	b.Policy = TestOnly
+	b.Reporter = LogReporter(log.New(os.Stdout, "", 0), Warn)
+	b.Output = WriteFile
+	b.FileName = "mocks_test.go"
+	b.Prefix = "Mock"
+	b.Recorder = RecordCalls
+	b.Format = true
+	b.Flavor = Classic
+	b.Flavors = make(map[string]Flavor)
+	b.Spies = make(map[string]bool)
+	b.Fixtures = make(map[string]bool)
+}
+
+// SetDepth sets how many levels of dependencies are mocked, 1 (default) mocks only direct dependencies and 0 only
+// the component itself
+func (b *builder) SetDepth(depth int) {
+	b.Depth = depth
 }
 
 func (b *builder) Register(entries []fuse.Entry) []error {
+	_, fn, _, _ := runtime.Caller(1)
+	if err := b.Policy(fn); err != nil {
+		b.fail("", err)
+		return b.Errors
+	}
 	for i := 0; i < len(entries); i++ {
-		_, fn, _, _ := runtime.Caller(1)
-		if !strings.Contains(fn, "_test.go") {
-			panic("RegisterMock can only bs used from within test code, not production code")
-		}
-		fmt.Printf("Starting to register %s\n", entries[i].Name)
 		b.register3(entries[i])
-		fmt.Printf("Ending to register %s\n", entries[i].Name)
 	}
 	return b.Errors
 }
 
 func (b *builder) register3(entry fuse.Entry) {
 	t := reflect.TypeOf(entry.Instance)
-	v := t.Elem()
-	spl := strings.Split(v.PkgPath(), b.Basepath)
-	if len(spl) == 0 {
-		e := fmt.Sprintf("entry [%s] has no basepath set", entry.Name)
-		b.Errors = append(b.Errors, errors.New(e))
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		e := fmt.Sprintf("entry [%s] can only be a pointer to a struct", entry.Name)
+		b.fail(entry.Name, errors.New(e))
+		return
+	}
+	p, err := b.dir(t.Elem().PkgPath())
+	if err != nil {
+		e := fmt.Sprintf("entry [%s] has no source directory: %s", entry.Name, err)
+		b.fail(entry.Name, errors.New(e))
 		return
 	}
-	p := "." + spl[len(spl)-1]
 	c := Component{Name: entry.Name, Instance: entry.Instance, Basepath: p}
 	b.Registry[entry.Name] = c
+	b.Reporter.Report(Event{Level: Info, Kind: EventRegistered, Component: entry.Name, Path: p})
+}
+
+// fail records a registration error and reports it
+func (b *builder) fail(component string, err error) {
+	b.Errors = append(b.Errors, err)
+	b.Reporter.Report(Event{Level: Error, Kind: EventError, Component: component, Message: err.Error()})
 }
 
 // Find is a Resource Locator of components
@@ -133,18 +203,126 @@ func (b *builder) Find(name string) interface{} {
 }
 
 func (b *builder) Generate() []error {
-	mockInfoMap = make(map[reflect.Type]*typeInfo)
-	for _, c := range b.Registry {
-		populateInfo(c)
+	g := b.Graph()
+	// in name order, a struct registered under several names is generated once under the first
+	b.infos = make(map[reflect.Type]*typeInfo)
+	for _, name := range g.Nodes {
+		c := b.Registry[name]
+		if info, ok := b.infos[reflect.TypeOf(c.Instance).Elem()]; ok {
+			info.Names = append(info.Names, name)
+			continue
+		}
+		info := populateInfo(c)
+		info.MockName = b.Prefix + info.StructName + b.Suffix
+		info.Names = []string{name}
+		b.infos[info.Typ] = info
 	}
-	for t, info := range mockInfoMap {
-		//if strings.Contains(t.String(), "Control") {
-		gen(t, info)
-		//	break
-		//}
+	errs := make([]error, 0)
+	tmpls := make(map[Flavor]*template.Template)
+	flavors := []Flavor{b.Flavor}
+	for _, f := range b.Flavors {
+		flavors = append(flavors, f)
+	}
+	for _, f := range flavors {
+		if _, ok := tmpls[f]; ok {
+			continue
+		}
+		tmpl, err := b.parseTemplates(f)
+		if err != nil {
+			b.Reporter.Report(Event{Level: Error, Kind: EventError, Message: err.Error()})
+			return append(errs, err)
+		}
+		tmpls[f] = tmpl
+	}
+	// generate in component name order so that output is stable, components sharing a file name are merged
+	files := make(map[string][]*genInfo)
+	paths := make([]string, 0)
+	for _, name := range g.Nodes {
+		t := reflect.TypeOf(b.Registry[name].Instance).Elem()
+		info := b.infos[t]
+		if info.Name != name {
+			// generated under its first name
+			continue
+		}
+		path := b.path(info)
+		b.Reporter.Report(Event{Level: Debug, Kind: EventGenerating, Component: name, Path: path})
+		for _, field := range info.Unfaked {
+			b.Reporter.Report(Event{Level: Warn, Kind: EventSkipped, Component: name,
+				Message: fmt.Sprintf("variadic func field %s is not faked", field)})
+		}
+		if _, ok := files[path]; !ok {
+			paths = append(paths, path)
+		}
+		files[path] = append(files[path], b.enclosed(t, info, g))
+	}
+	sort.Strings(paths)
+	if err := b.checkFlavors(paths, files); err != nil {
+		b.Reporter.Report(Event{Level: Error, Kind: EventError, Message: err.Error()})
+		return append(errs, err)
+	}
+	for _, ginfo := range layout(paths, files) {
+		if err := b.gen(ginfo, tmpls[b.flavor(ginfo.EnclosingType.Name)]); err != nil {
+			b.Reporter.Report(Event{Level: Error, Kind: EventError, Component: ginfo.EnclosingType.Name, Message: err.Error()})
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
 
+// path is the file the mock of a component is generated into
+func (b *builder) path(info *typeInfo) string {
+	name := b.FileName
+	if strings.Contains(name, "%s") {
+		name = fmt.Sprintf(name, strings.ToLower(info.Name))
 	}
-	return nil
+	return filepath.Join(info.Basepath, name)
+}
+
+// layout lays the mocks of a package out over its files so that each is declared once: the mock of a component
+// goes into the component's file and the mock of a dependency into the first file needing it. The first file of a
+// package also declares the recorder and defaults shared by all of them
+func layout(paths []string, files map[string][]*genInfo) []*genInfo {
+	owners := make(map[string]map[reflect.Type]string)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if owners[dir] == nil {
+			owners[dir] = make(map[reflect.Type]string)
+		}
+		for _, c := range files[path] {
+			owners[dir][c.EnclosingType.Typ] = path
+		}
+	}
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		for _, c := range files[path] {
+			for _, info := range c.EnclosedTypes {
+				if _, ok := owners[dir][info.Typ]; !ok {
+					owners[dir][info.Typ] = path
+				}
+			}
+		}
+	}
+	gen := make([]*genInfo, 0, len(paths))
+	funcs := make(map[string]map[string]string)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		c := files[path][0]
+		ginfo := &genInfo{EnclosingType: c.EnclosingType, EnclosedTypes: make(map[reflect.Type]*typeInfo),
+			Record: c.Record, Path: path, Shared: funcs[dir] == nil}
+		if ginfo.Shared {
+			funcs[dir] = make(map[string]string)
+		}
+		ginfo.Funcs = funcs[dir]
+		for _, c := range files[path] {
+			for t, info := range c.EnclosedTypes {
+				if owners[dir][info.Typ] == path {
+					ginfo.EnclosedTypes[t] = info
+				}
+			}
+		}
+		gen = append(gen, ginfo)
+	}
+	return gen
 }
 
 // populateInfo populates type information
@@ -155,7 +333,6 @@ func populateInfo(c Component) *typeInfo {
 	tval := reflect.TypeOf(v1)
 	info := &typeInfo{Typ: tval, PTyp: tptr, Name: c.Name, StructName: tval.Name(), PkgPath: tval.PkgPath(), PkgString: tval.String(), Pkg: pkg(tval.String()),
 		Basepath: c.Basepath}
-	mockInfoMap[tval] = info
 	// navigate value receiver as well as pointer receiver, to get ALL methods
 	types := []reflect.Type{tval, tptr}
 	// populate
@@ -173,21 +350,18 @@ func populateInfo(c Component) *typeInfo {
 			// populate all input parameters
 			for j := 0; j < t1.NumIn(); j++ {
 				t2 := t1.In(j)
-				if t2.PkgPath() != "" {
-					info.Imports = append(info.Imports, t2.PkgPath())
-				}
+				info.Imports = pkgPaths(t2, info.Imports)
 				ptr := false
 				if reflect.Ptr == t2.Kind() {
 					ptr = true
 				}
-				fn.Params = append(fn.Params, &param{Input: true, Typ: t2, Name: t2.Name(), Ptr: ptr})
+				fn.Params = append(fn.Params, &param{Input: true, Typ: t2, Name: t2.Name(), Ptr: ptr,
+					Variadic: t1.IsVariadic() && j == t1.NumIn()-1})
 			}
 			// populate all output parameters
 			for j := 0; j < t1.NumOut(); j++ {
 				t2 := t1.Out(j)
-				if t2.PkgPath() != "" {
-					info.Imports = append(info.Imports, t2.PkgPath())
-				}
+				info.Imports = pkgPaths(t2, info.Imports)
 				ptr := false
 				if reflect.Ptr == t2.Kind() {
 					ptr = true
@@ -197,60 +371,117 @@ func populateInfo(c Component) *typeInfo {
 		}
 		info.Fields = populateFields(info, tptr)
 	}
+	populateFakes(info)
 	return info
 
 }
 
-func gen(t reflect.Type, info *typeInfo) {
-	funcMap["printOutParams"] = printOutParams
-	funcMap["printInParams"] = printInParams
-	funcMap["printInNames"] = printInNames
-	funcMap["paramSlice"] = paramSlice
-	funcMap["receiver"] = receiver
-	funcMap["printFields"] = printFields
-	funcMap["printImports"] = printImports
+// named tells whether the struct is registered under name
+func (info *typeInfo) named(name string) bool {
+	for _, n := range info.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
 
-	tmpl, err := template.New("test").Funcs(funcMap).Parse(letter)
-	if err != nil {
-		log.Fatalf("parsing: %s", err)
+// populateFakes adds a fake method, e.g. FakeNow, for every func field marked with a `_fuse` or `_fake` tag.
+// The receiver of fakes is the pointer to the component, variadic funcs are not faked but listed in Unfaked
+func populateFakes(info *typeInfo) {
+	for _, f := range info.Fields {
+		_, fuse := f.StructField.Tag.Lookup("_fuse")
+		_, fake := f.StructField.Tag.Lookup("_fake")
+		if f.Typ.Kind() != reflect.Func || !fuse && !fake || fnExists(info, "Fake"+f.Name) {
+			continue
+		}
+		if f.Typ.IsVariadic() {
+			info.Unfaked = append(info.Unfaked, f.Name)
+			continue
+		}
+		fn := &funcInfo{Name: "Fake" + f.Name, Field: f.Name}
+		fn.Params = append(fn.Params, &param{Input: true, Typ: info.PTyp, Name: info.StructName, Ptr: true})
+		for j := 0; j < f.Typ.NumIn(); j++ {
+			t := f.Typ.In(j)
+			fn.Params = append(fn.Params, &param{Input: true, Typ: t, Name: t.Name(), Ptr: t.Kind() == reflect.Ptr})
+		}
+		for j := 0; j < f.Typ.NumOut(); j++ {
+			t := f.Typ.Out(j)
+			fn.Params = append(fn.Params, &param{Input: false, Typ: t, Name: t.Name(), Ptr: t.Kind() == reflect.Ptr})
+		}
+		for _, p := range fn.Params[1:] {
+			info.Imports = pkgPaths(p.Typ, info.Imports)
+		}
+		info.Funcs = append(info.Funcs, fn)
 	}
-	fmt.Println("Type being genned ", info.Typ)
+}
 
-	ginfo := genInfo{EnclosingType: info}
+// enclosed collects the component and the dependencies mocked alongside it, up to the builder's Depth
+func (b *builder) enclosed(t reflect.Type, info *typeInfo, g *Graph) *genInfo {
+	ginfo := genInfo{EnclosingType: info, Record: b.Recorder == RecordCalls}
 	ginfo.EnclosedTypes = make(map[reflect.Type]*typeInfo, 0)
 	ginfo.EnclosedTypes[t] = info
 	for _, f := range info.Fields {
-		if _, ok := f.StructField.Tag.Lookup("_fuse"); !ok {
+		if b.Depth == 0 || !dependency(f.StructField) {
 			continue
 		}
 		temp := f.Typ
 		if f.Typ.Kind() == reflect.Ptr {
 			temp = f.Typ.Elem()
 		}
-		popEnclosed(temp, &ginfo)
+		b.popEnclosed(temp, &ginfo)
 	}
 	for _, f := range info.Fields {
-		if "DEPS_" != f.Name {
+		if b.Depth == 0 || "DEPS_" != f.Name {
 			continue
 		}
 		deps := findDeps(f)
 		for _, dep := range deps {
-			for t, v := range mockInfoMap {
-				if dep == v.Name {
+			for t, v := range b.infos {
+				if v.named(dep) {
+					b.resolved(&ginfo, v, t)
+					ginfo.EnclosedTypes[t] = v
+				}
+			}
+		}
+	}
+	// transitive dependencies beyond the direct ones
+	if b.Depth < 0 || b.Depth > 1 {
+		for _, dep := range g.Reach(info.Name, b.Depth) {
+			for t, v := range b.infos {
+				if v.named(dep) && shouldAdd(ginfo.EnclosedTypes, v) {
+					b.resolved(&ginfo, v, t)
 					ginfo.EnclosedTypes[t] = v
 				}
 			}
 		}
 	}
-	var b bytes.Buffer
-	err = tmpl.Execute(&b, ginfo)
+	return &ginfo
+}
+
+func (b *builder) gen(ginfo *genInfo, tmpl *template.Template) error {
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "file", b.fileData(ginfo))
 	if err != nil {
-		log.Fatalf("execution: %s", err)
+		return fmt.Errorf("execution: %s", err)
 	}
-	//s := b.String()
-	//fmt.Println(s)
-	err = ioutil.WriteFile(info.Basepath+"/mocks_test.go", b.Bytes(), 0644)
-	fmt.Println(err)
+	src := buf.Bytes()
+	if b.Format {
+		if src, err = format.Source(src); err != nil {
+			return fmt.Errorf("formatting mock for %s: %s", ginfo.EnclosingType.Name, err)
+		}
+	}
+	if err = b.Output(ginfo.Path, src); err != nil {
+		return err
+	}
+	b.Reporter.Report(Event{Level: Info, Kind: EventWritten, Component: ginfo.EnclosingType.Name, Path: ginfo.Path})
+	return nil
+}
+
+// dependency tells if a field refers to another component, either by a `_fuse` tag or by embedding an interface
+func dependency(f reflect.StructField) bool {
+	_, ok := f.Tag.Lookup("_fuse")
+	return ok || f.Anonymous && f.Type.Kind() == reflect.Interface
 }
 
 // findDeps finds stateless dependencies
@@ -264,20 +495,18 @@ func findDeps(info *fieldInfo) []string {
 }
 
 // popEnclosed populates properties of components, either structs or interfaces
-func popEnclosed(temp reflect.Type, ginfo *genInfo) {
-	if pi, ok := mockInfoMap[temp]; ok {
-		fmt.Println("contains ", temp, "  ", pi.Typ)
+func (b *builder) popEnclosed(temp reflect.Type, ginfo *genInfo) {
+	if pi, ok := b.infos[temp]; ok {
 		if shouldAdd(ginfo.EnclosedTypes, pi) {
-			fmt.Println("assignable = ", pi, "  ", temp)
+			b.resolved(ginfo, pi, temp)
 			ginfo.EnclosedTypes[temp] = pi
 		}
 	}
 	if temp.Kind() == reflect.Interface {
-		for _, v := range mockInfoMap {
-			fmt.Println("contains ", temp, "  ", v.Typ)
+		for _, v := range b.infos {
 			if v.PTyp.AssignableTo(temp) {
-				fmt.Println("assignable = ", v.Typ, "  ", temp)
 				if shouldAdd(ginfo.EnclosedTypes, v) {
+					b.resolved(ginfo, v, temp)
 					ginfo.EnclosedTypes[temp] = v
 				}
 			}
@@ -285,6 +514,11 @@ func popEnclosed(temp reflect.Type, ginfo *genInfo) {
 	}
 }
 
+// resolved reports a dependency of the enclosing component that gets mocked alongside it
+func (b *builder) resolved(ginfo *genInfo, dep *typeInfo, t reflect.Type) {
+	b.Reporter.Report(Event{Level: Debug, Kind: EventResolved, Component: ginfo.EnclosingType.Name, Dependency: dep.Name, Message: t.String()})
+}
+
 func shouldAdd(types map[reflect.Type]*typeInfo, pi *typeInfo) bool {
 	for _, v := range types {
 		if v.Typ == pi.Typ {
@@ -303,24 +537,61 @@ func pkg(basepath string) string {
 }
 
 const letter = `
-{{$str:=""}}
-package {{.EnclosingType.Pkg}}
+{{define "file"}}
+{{template "header" .}}
+{{if .Shared}}{{if .Record}}{{template "recorder" .}}{{end}}
+{{template "defaults" .}}{{end}}
+{{range .Mocks}}{{template "mock" .}}{{end}}
+{{end}}
+
+{{define "header"}}
+package {{.Package}}
 import (
-{{.EnclosedTypes | printImports}}
-)
+{{range .Imports}}"{{.}}"
+{{end}})
+{{end}}
 
+{{define "recorder"}}
 // Start of method calls and parameter capture
 var stats = make(map[string]*funcCalls, 0)
 
+// calls holds every call of every mock in order
+var calls = make([]*CallInfo, 0)
+
+var statsMu sync.Mutex
+
+// seq counts the calls captured so far
+var seq int64
+
+// marks are the seq of tests when they attached, by test name
+var marks = make(map[string]int64)
+
+// ArgSnapshots selects the methods whose arguments are deep copied when captured, none by default
+var ArgSnapshots = &{{.Runtime}}Snapshots{}
+
 type funcCalls struct {
 	Count  int
 	Params [][]interface{}
+	Calls  []*CallInfo
 }
 
+// CallInfo is the record of one call of a mock method
 type CallInfo struct {
-	Ok     bool
-	Name   string
-	Params []interface{}
+	// Ok is set when the call returned normally
+	Ok bool
+	// Name is the key of the method, e.g. MockL1_LM1
+	Name    string
+	Params  []interface{}
+	Results []interface{}
+	// Panic is the value the call panicked with
+	Panic interface{}
+	Time  time.Time
+	// Goroutine is the id of the goroutine the call was made on
+	Goroutine int64
+	// Caller is the file:line the mock was called from
+	Caller string
+	// Seq numbers the calls of all mocks in order, from 1
+	Seq int64
 }
 
 type Params []interface{}
@@ -342,7 +613,38 @@ func CallParams(name string) []Params {
 	return []Params{}
 }
 
-func capture(key string, params []interface{}) {
+// Calls returns the records of the calls of a method in order
+func Calls(name string) []CallInfo {
+	call := forCall(name)
+	return copyCalls(call.Calls)
+}
+
+// AllCalls returns the records of the calls of all mocks in order
+func AllCalls() []CallInfo {
+	statsMu.Lock()
+	all := calls
+	statsMu.Unlock()
+	return copyCalls(all)
+}
+
+func copyCalls(records []*CallInfo) []CallInfo {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	infos := make([]CallInfo, 0, len(records))
+	for _, c := range records {
+		infos = append(infos, *c)
+	}
+	return infos
+}
+
+func capture(key string, params []interface{}) *CallInfo {
+	params = ArgSnapshots.Copy(key, params)
+	call := &CallInfo{Name: key, Params: params, Time: time.Now(), Goroutine: goroutine()}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		call.Caller = file + ":" + strconv.Itoa(line)
+	}
+	statsMu.Lock()
+	defer statsMu.Unlock()
 	val, ok := stats[key]
 	if !ok {
 		val = &funcCalls{}
@@ -352,39 +654,490 @@ func capture(key string, params []interface{}) {
 	}
 	val.Count++
 	val.Params = append(val.Params, params)
+	val.Calls = append(val.Calls, call)
+	seq++
+	call.Seq = seq
+	calls = append(calls, call)
+	return call
+}
+
+// done records the results of a call, or its panic which is then resumed
+func (c *CallInfo) done(p interface{}, results ...interface{}) {
+	statsMu.Lock()
+	c.Ok = p == nil
+	c.Panic = p
+	c.Results = results
+	statsMu.Unlock()
+	if p != nil {
+		panic(p)
+	}
+}
+
+func goroutine() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	fields := strings.Fields(strings.TrimPrefix(string(buf), "goroutine "))
+	id, _ := strconv.ParseInt(fields[0], 10, 64)
+	return id
+}
+
+func init() {
+	// -update rewrites the golden files of AssertCallLog, unless the test package defines the flag itself
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "rewrite golden files")
+	}
+	resets = append(resets, resetCalls)
+}
+
+// resetCalls forgets the calls recorded so far
+func resetCalls() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats = make(map[string]*funcCalls, 0)
+	calls = make([]*CallInfo, 0)
+}
+
+// AssertCallLog compares the log of the calls made during t in order with the golden file testdata/<name>.golden,
+// running the tests with -update rewrites it. Calls are logged from Attach of t or of a parent test, or else since
+// the mocks were last reset
+func AssertCallLog(t testing.TB, name string) {
+	t.Helper()
+	{{.Runtime}}AssertGolden(t, name, {{.Runtime}}CallLog(loggedCalls(since(t))))
+}
 
+// Attach marks the start of the calls logged for t and, when t has failed, logs a table of the calls per mock made
+// since. It leaves the mocks as they are, register ResetMocks with t.Cleanup to reset them
+func Attach(t testing.TB) {
+	statsMu.Lock()
+	mark := seq
+	marks[t.Name()] = mark
+	statsMu.Unlock()
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("calls of mocks:\n%s", {{.Runtime}}CallTable(loggedCalls(mark)))
+		}
+		statsMu.Lock()
+		delete(marks, t.Name())
+		statsMu.Unlock()
+	})
+}
+
+// since returns the seq t or its closest parent attached at, 0 when none did
+func since(t testing.TB) int64 {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	name := t.Name()
+	for {
+		if mark, ok := marks[name]; ok {
+			return mark
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return 0
+		}
+		name = name[:i]
+	}
+}
+
+// loggedCalls returns the calls after the mark in order
+func loggedCalls(mark int64) []{{.Runtime}}LoggedCall {
+	log := make([]{{.Runtime}}LoggedCall, 0)
+	for _, c := range AllCalls() {
+		if c.Seq > mark {
+			log = append(log, {{.Runtime}}LoggedCall{Name: c.Name, Args: c.Params, Results: c.Results, Panic: c.Panic, Caller: c.Caller})
+		}
+	}
+	return log
 }
 
 func forCall(key string) funcCalls {
+	statsMu.Lock()
+	defer statsMu.Unlock()
 	if val, ok := stats[key]; ok {
 		return *val
 	}
 	return funcCalls{}
 }
+
+// returns holds the results of a method configured per call, in sequence or per arguments. Calls are counted
+// from the first configured result, base is the number of calls made before
+type returns struct {
+	outs     int
+	started  bool
+	base     int
+	onCall   map[int][]interface{}
+	sequence [][]interface{}
+	when     []whenReturns
+}
+
+type whenReturns struct {
+	params  []interface{}
+	results []interface{}
+}
+
+// results finds the results of the current call of key, by arguments first and then by call number.
+// It returns nil when none are configured, and fails when the configured sequence is exhausted
+func (r *returns) results(d *Defaults, key string, params []interface{}) []interface{} {
+	for _, w := range r.when {
+		if reflect.DeepEqual(w.params, params) {
+			return w.results
+		}
+	}
+	n := NumCalls(key) - 1 - r.base
+	if res, ok := r.onCall[n]; ok {
+		return res
+	}
+	if n < len(r.sequence) {
+		return r.sequence[n]
+	}
+	if len(r.sequence) == 0 {
+		return nil
+	}
+	if d == nil {
+		d = UnstubbedDefaults
+	}
+	msg := fmt.Sprintf("%s call %d with %v exhausted the sequence of %d results", key, n, params, len(r.sequence))
+	if d.Fail == nil {
+		panic(msg)
+	}
+	d.Fail("%s", msg)
+	return make([]interface{}, r.outs)
+}
+
+// start counts calls of key from now on, unless results were configured before
+func (r *returns) start(key string) {
+	if !r.started {
+		r.started = true
+		r.base = NumCalls(key)
+	}
+}
+
+// reset forgets the configured results
+func (r *returns) reset() {
+	*r = returns{outs: r.outs}
+}
 // End of method calls and parameter capture
-{{range .EnclosedTypes}}
-// Begin of mock for {{.StructName}} and its methods
-type Mock{{.StructName}} struct{
-	{{.Fields | printFields }}
+{{end}}
+
+{{define "defaults"}}
+// Start of defaults for methods without a stub
+// Defaults decides what mocks return from methods without a stub, zero values unless configured
+type Defaults struct {
+	// Fail, when set, is called with the method and its arguments, e.g. t.Fatalf
+	Fail func(format string, args ...interface{})
+	// Values are returned for results of their type
+	Values map[reflect.Type]interface{}
 }
-{{$str:=.StructName}}
-{{range .Funcs}}
-{{$rec:= . | receiver}}
 
+// UnstubbedDefaults applies to mocks without Defaults of their own
+var UnstubbedDefaults = &Defaults{}
+
+// resets clear the stubs of mock methods and what is configured for them, and the recorded calls
+var resets = make([]func(), 0)
+
+// ResetMocks clears the stubs of every mock method and what is configured for them, e.g. results, faults and
+// latencies, and forgets the recorded calls. Register it with t.Cleanup
+func ResetMocks() {
+	for _, reset := range resets {
+		reset()
+	}
+}
 
-type {{.Name}} func({{.Params | printInParams}}) {{.Params | printOutParams}}
-var Mock{{$str}}_{{.Name}} {{.Name}}
-func ({{$rec}}Mock{{$str}}) {{.Name}}({{.Params | printInParams}}) {{.Params | printOutParams}} {
-	capture("Mock{{$str}}_{{.Name}}", {{.Params | paramSlice}})
-	return Mock{{$str}}_{{.Name}}({{.Params | printInNames}})
+// Return sets the value returned for results of the type ptr points to, e.g. Return((*error)(nil), errSentinel).
+// It panics when ptr is not a pointer or value is not assignable to the type
+func (d *Defaults) Return(ptr interface{}, value interface{}) *Defaults {
+	t := reflect.TypeOf(ptr)
+	if t == nil || t.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("Defaults.Return: %T should have been a pointer to the type of the results", ptr))
+	}
+	if value != nil && !reflect.TypeOf(value).AssignableTo(t.Elem()) {
+		panic(fmt.Sprintf("Defaults.Return: %T is not assignable to results of type %s", value, t.Elem()))
+	}
+	if d.Values == nil {
+		d.Values = make(map[reflect.Type]interface{})
+	}
+	d.Values[t.Elem()] = value
+	return d
+}
+
+func unstubbed(d *Defaults, key string, params []interface{}, results ...interface{}) {
+	if d == nil {
+		d = UnstubbedDefaults
+	}
+	if d.Fail != nil {
+		d.Fail("%s called with %v, but has no stub", key, params)
+	}
+	for _, r := range results {
+		v := reflect.ValueOf(r).Elem()
+		if val, ok := d.Values[v.Type()]; ok && val != nil {
+			v.Set(reflect.ValueOf(val))
+		}
+	}
 }
+
+// failed reports a failed call through d.Fail, or panics when it is not set
+func failed(d *Defaults, format string, args ...interface{}) {
+	if d == nil {
+		d = UnstubbedDefaults
+	}
+	if d.Fail == nil {
+		panic(fmt.Sprintf(format, args...))
+	}
+	d.Fail(format, args...)
+}
+// End of defaults for methods without a stub
 {{end}}
-// End of mock for {{$str}} and its methods
+
+{{define "mock"}}
+// Begin of mock for {{.Struct}} and its methods
+type {{.Name}} struct{
+{{range .Fields}}{{if .Embedded}}{{.Type}}{{else}}{{.Name}} {{.Type}}{{end}}
+{{end}}{{if .Spy}}// Spied is the real component, called when no stub is set
+Spied *{{.Real}}
+{{end}}{{if .Fixture}}// Fixture, when set, replays recorded results for calls without a stub
+Fixture *{{.File.Runtime}}Fixture
+{{end}}// Defaults overrides UnstubbedDefaults for this mock
+Defaults *Defaults
+}
+{{if .Spy}}
+// New{{.Name}}Spy returns a mock delegating to real unless a stub is set
+func New{{.Name}}Spy(real *{{.Real}}) *{{.Name}} {
+	{{if .Fakes}}m := &{{.Name}}{Spied: real}
+	{{range .Fakes}}m.{{.Field}} = m.{{.Name}}
+	{{end}}return m{{else}}return &{{.Name}}{Spied: real}{{end}}
+}
+{{end}}{{if .Fakes}}
+// New{{.Name}} returns a mock with its func fields wired to their recording fakes
+func New{{.Name}}() *{{.Name}} {
+	m := &{{.Name}}{}
+	{{range .Fakes}}m.{{.Field}} = m.{{.Name}}
+	{{end}}return m
+}
+{{end}}
+// init provides {{.Name}} as the mock of {{.Component}} to Swap
+func init() {
+	{{- range .Components}}
+	{{$.File.Runtime}}RegisterMockFactory("{{$.PkgPath}}", "{{.}}", func() interface{} {
+		return {{if $.Fakes}}New{{$.Name}}(){{else}}&{{$.Name}}{}{{end}}
+	})
+	{{- end}}
+}
+{{range .Methods}}{{template "method" .}}{{end}}
+{{- if .Fixture}}{{template "recording" .}}{{end}}
+// End of mock for {{.Struct}} and its methods
+{{end}}
+
+{{define "method"}}
+{{if .DeclareFunc}}type {{.Func}} func({{.Params}}) {{.Results}}
+{{end}}var {{.Stub}} {{.Func}}
+
+// {{.Stub}}_Faults are injected into calls of {{.Name}} before the stub is invoked
+var {{.Stub}}_Faults {{.Mock.File.Runtime}}Faults
+{{if .Ctx}}
+// {{.Stub}}_Latency is waited for by calls of {{.Name}} before the stub is invoked
+var {{.Stub}}_Latency {{.Mock.File.Runtime}}Latency
+{{end}}{{if .Feed}}
+// {{.Stub}}_Feed is the channel {{.Name}} returns as {{.Feed.Name}} when it has no stub and values were sent
+var {{.Stub}}_Feed = {{.Mock.File.Runtime}}NewFeed((chan {{.Feed.Elem}})(nil))
+
+// {{.Name}}Send queues values, delivered in order into the channel {{.Name}} returns
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}Send(values ...{{.Feed.Elem}}) {
+	for _, v := range values {
+		{{.Stub}}_Feed.Send(v)
+	}
+}
+
+// {{.Name}}Close closes the channel {{.Name}} returns once the queued values are delivered
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}Close() {
+	{{.Stub}}_Feed.Close()
+}
+{{end}}{{range .In}}{{if .Callback}}
+// {{$.Stub}}_{{.Field}}_Callbacks are the invocations of the argument {{.Name}} of every call of {{$.Name}}
+var {{$.Stub}}_{{.Field}}_Callbacks {{$.Mock.File.Runtime}}Callbacks
+
+// {{$.Name}}Invokes{{.Field}} makes calls of {{$.Name}} invoke their argument {{.Name}} with the given arguments,
+// after the invocations configured before
+func ({{$.Receiver}}{{$.Mock.Name}}) {{$.Name}}Invokes{{.Field}}({{range $i, $a := .FuncIn}}{{if $i}}, {{end}}{{$a.Name}} {{$a.Type}}{{end}}) {
+	{{$.Stub}}_{{.Field}}_Callbacks.Add({{range $i, $a := .FuncIn}}{{if $i}}, {{end}}{{$a.Name}}{{end}})
+}
+{{end}}{{end}}{{if .Writable}}
+// {{.Stub}}_ArgWrites are written into the arguments of calls of {{.Name}} before the stub is invoked
+var {{.Stub}}_ArgWrites {{.Mock.File.Runtime}}ArgWrites
+
+// {{.Name}}SetsArg writes value into the nth argument of calls of {{.Name}}, counting from 0
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}SetsArg(n int, value interface{}) {
+	{{.Stub}}_ArgWrites.Set(n, value)
+}
+{{end}}func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}({{.Params}}) {{if .Out}}({{template "rets" .Out}}){{end}} {
+	{{if .Mock.File.Record}}call := capture("{{.Stub}}", {{.Args}})
+	defer func() {
+		call.done(recover(){{range $i, $o := .Out}}, ret{{$i}}{{end}})
+	}(){{end}}
+	if {{.Stub}}_Faults.Inject({{.Args}}, {{if .Err}}&{{.Err}}{{else}}nil{{end}}) {
+		return
+	}
+	{{- if .Ctx}}
+	if err := {{.Stub}}_Latency.Wait({{.Ctx}}); err != nil {
+		{{if .Err}}{{.Err}} = err
+		{{end}}return
+	}{{end}}
+	{{- if .Writable}}
+	if err := {{.Stub}}_ArgWrites.Apply({{.Args}}); err != nil {
+		failed({{.Recv}}.Defaults, "{{.Stub}} %s", err)
+	}{{end}}
+	{{- range .In}}{{if .Callback}}
+	{{if $.Err}}if err := {{$.Stub}}_{{.Field}}_Callbacks.Invoke({{.Name}}); err != nil {
+		{{$.Err}} = err
+		return
+	}{{else}}if err := {{$.Stub}}_{{.Field}}_Callbacks.Invoke({{.Name}}); err != nil {
+		failed({{$.Recv}}.Defaults, "{{$.Stub}} %s", err)
+	}{{end}}{{end}}{{end}}
+	{{- if and .Mock.Spy (not .Field)}}
+	if {{.Stub}} == nil && {{.Recv}}.Spied != nil {
+		{{if .Out}}return {{end}}{{.Recv}}.Spied.{{.Name}}({{.Names}}){{if not .Out}}
+		return{{end}}
+	}{{end}}
+	{{- if and .Mock.Fixture (not .Field)}}
+	if {{.Stub}} == nil && {{.Recv}}.Fixture != nil {
+		if err := {{.Recv}}.Fixture.Replay("{{.Name}}", {{.Args}}{{range $i, $o := .Out}}, &ret{{$i}}{{end}}); err != nil {
+			failed({{.Recv}}.Defaults, "%s", err)
+		}
+		return
+	}{{end}}
+	{{- if .Feed}}
+	if {{.Stub}} == nil && {{.Stub}}_Feed.Fed() {
+		{{.Feed.Name}} = {{.Stub}}_Feed.Chan().(chan {{.Feed.Elem}})
+		return
+	}{{end}}
+	if {{.Stub}} == nil {
+		unstubbed({{.Recv}}.Defaults, "{{.Stub}}", {{.Args}}{{range $i, $o := .Out}}, &ret{{$i}}{{end}})
+		return
+	}
+	{{if .Out}}return {{end}}{{.Stub}}({{.Names}})
+}
+{{if .Mock.File.Record}}{{template "calls" .}}{{end}}
+{{- if and .Mock.File.Record .Out}}{{template "returns" .}}{{end}}
+// {{.Stub}}_Reset clears the stub of {{.Name}} and what is configured for it
+func {{.Stub}}_Reset() {
+	{{.Stub}} = nil
+	{{.Stub}}_Faults.Reset()
+	{{- if .Ctx}}
+	{{.Stub}}_Latency.Reset(){{end}}
+	{{- if .Writable}}
+	{{.Stub}}_ArgWrites.Reset(){{end}}
+	{{- if .Feed}}
+	{{.Stub}}_Feed.Reset(){{end}}
+	{{- range .In}}{{if .Callback}}
+	{{$.Stub}}_{{.Field}}_Callbacks.Reset(){{end}}{{end}}
+	{{- if and .Mock.File.Record .Out}}
+	{{.Stub}}_Returns.reset(){{end}}
+}
+
+func init() {
+	resets = append(resets, {{.Stub}}_Reset)
+}
+{{end}}
+
+{{define "recording"}}
+// {{.Name}}Recording wraps the real component, recording every call into Fixture
+type {{.Name}}Recording struct {
+	Real    *{{.Real}}
+	Fixture *{{.File.Runtime}}Fixture
+}
+{{range .Methods}}{{if not .Field}}
+func (rec *{{.Mock.Name}}Recording) {{.Name}}({{.Params}}) {{if .Out}}({{template "rets" .Out}}){{end}} {
+	{{if .Out}}{{template "retNames" .Out}} = {{end}}rec.Real.{{.Name}}({{.Names}})
+	rec.Fixture.Record("{{.Name}}", {{.Args}}{{range $i, $o := .Out}}, ret{{$i}}{{end}})
+	return
+}
+{{end}}{{end}}{{end}}
+
+{{define "calls"}}
+// {{.Mock.Name}}{{.Name}}Call is a recorded call of {{.Name}}, results are zero when the call did not return
+type {{.Mock.Name}}{{.Name}}Call struct {
+	{{range .In}}{{.Field}} {{.Type}}
+	{{end}}{{range .Out}}{{.Field}} {{.Type}}
+	{{end}}
+}
+
+// {{.Name}}Calls returns the recorded calls of {{.Name}} in order
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}Calls() []{{.Mock.Name}}{{.Name}}Call {
+	calls := make([]{{.Mock.Name}}{{.Name}}Call, 0)
+	for _, c := range Calls("{{.Stub}}") {
+		call := {{.Mock.Name}}{{.Name}}Call{}
+		{{range $i, $p := .In}}call.{{$p.Field}}, _ = c.Params[{{$i}}].({{$p.Type}})
+		{{end}}{{if .Out}}if len(c.Results) == {{len .Out}} {
+			{{- range $i, $o := .Out}}
+			call.{{$o.Field}}, _ = c.Results[{{$i}}].({{$o.Type}}){{end}}
+		}
+		{{end}}calls = append(calls, call)
+	}
+	return calls
+}
+
+// {{.Name}}CallCount returns the number of calls of {{.Name}}
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}CallCount() int {
+	return NumCalls("{{.Stub}}")
+}
+{{end}}
+
+{{define "unstubbed"}}{{range $i, $o := .Out}}var ret{{$i}} {{$o.Type}}
+		{{end}}unstubbed({{.Recv}}.Defaults, "{{.Stub}}", {{.Args}}{{range $i, $o := .Out}}, &ret{{$i}}{{end}})
+		return {{template "retNames" .Out}}{{end}}
+
+{{define "rets"}}{{range $i, $o := .}}{{if $i}}, {{end}}ret{{$i}} {{$o.Type}}{{end}}{{end}}
+
+{{define "retNames"}}{{range $i, $o := .}}{{if $i}}, {{end}}ret{{$i}}{{end}}{{end}}
+
+{{define "returns"}}
+var {{.Stub}}_Returns = &returns{outs: {{len .Out}}}
+
+// {{.Stub}}_Results are the results of one call of {{.Name}}
+type {{.Stub}}_Results struct {
+	{{range $i, $o := .Out}}R{{$i}} {{$o.Type}}
+	{{end}}
+}
+
+// {{.Name}}ReturnsOnCall sets the results of the nth call of {{.Name}}, counting from 0
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}ReturnsOnCall(n int, {{template "rets" .Out}}) {
+	if {{.Stub}}_Returns.onCall == nil {
+		{{.Stub}}_Returns.onCall = make(map[int][]interface{})
+	}
+	{{.Stub}}_Returns.onCall[n] = []interface{}{ {{- template "retNames" .Out}}}
+	{{.Recv}}.use{{.Name}}Returns()
+}
+
+// {{.Name}}ReturnsSequence sets the results of successive calls of {{.Name}}, calls beyond the sequence fail
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}ReturnsSequence(results ...{{.Stub}}_Results) {
+	for _, r := range results {
+		{{.Stub}}_Returns.sequence = append({{.Stub}}_Returns.sequence, []interface{}{ {{- range $i, $o := .Out}}{{if $i}}, {{end}}r.R{{$i}}{{end}}})
+	}
+	{{.Recv}}.use{{.Name}}Returns()
+}
+
+// {{.Name}}ReturnsWhen sets the results of calls of {{.Name}} with the given arguments
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}ReturnsWhen({{.WhenParams}}{{if .In}}, {{end}}{{template "rets" .Out}}) {
+	w := whenReturns{params: {{.Args}}, results: []interface{}{ {{- template "retNames" .Out}}}}
+	{{.Stub}}_Returns.when = append({{.Stub}}_Returns.when, w)
+	{{.Recv}}.use{{.Name}}Returns()
+}
+
+func ({{.Receiver}}{{.Mock.Name}}) use{{.Name}}Returns() {
+	{{.Stub}}_Returns.start("{{.Stub}}")
+	{{.Stub}} = func({{.Params}}) {{.Results}} {
+		res := {{.Stub}}_Returns.results({{.Recv}}.Defaults, "{{.Stub}}", {{.Args}})
+		if res == nil {
+			{{template "unstubbed" .}}
+		}
+		{{range $i, $o := .Out}}ret{{$i}}, _ := res[{{$i}}].({{$o.Type}})
+		{{end}}return {{template "retNames" .Out}}
+	}
+}
 {{end}}
 `
 
-// printOutParams prints method output parameters
-func printOutParams(params []*param) string {
+// printOutParams prints method output parameters as referenced from the package with import path pkg
+func printOutParams(params []*param, pkg string) string {
 	if len(params) == 0 {
 		return ""
 	}
@@ -394,7 +1147,7 @@ func printOutParams(params []*param) string {
 		if p.Input {
 			continue
 		}
-		b.WriteString(p.Typ.String())
+		b.WriteString(typeName(p.Typ, pkg))
 		if i != len(params)-1 {
 			b.WriteString(",")
 		}
@@ -403,8 +1156,9 @@ func printOutParams(params []*param) string {
 	return b.String()
 }
 
-// printInParams prints method input parameters
-func printInParams(params []*param) string {
+// printInParams prints method input parameters as referenced from the package with import path pkg, named by their
+// position, e.g. a1. With spread the variadic input is printed as ...T, otherwise as []T
+func printInParams(params []*param, pkg string, spread bool) string {
 	if len(params) == 0 {
 		return ""
 	}
@@ -414,27 +1168,17 @@ func printInParams(params []*param) string {
 		if !p.Input {
 			continue
 		}
-		if p.Ptr {
-			inName := "p" + string(p.Typ.Elem().String()[0]) + strconv.Itoa(i)
-			b.WriteString(inName)
-			p.InName = inName
-		} else {
-			inName := string(p.Typ.String()[0]) + strconv.Itoa(i)
-			b.WriteString(inName)
-			p.InName = inName
-		}
+		p.InName = "a" + strconv.Itoa(i)
+		b.WriteString(p.InName)
 		b.WriteString(" ")
-		b.WriteString(p.Typ.String())
-		if i != len(params)-1 {
-			b.WriteString(",")
+		if p.Variadic && spread {
+			b.WriteString("..." + typeName(p.Typ.Elem(), pkg))
+		} else {
+			b.WriteString(typeName(p.Typ, pkg))
 		}
+		b.WriteString(",")
 	}
-	s := b.String()
-	if len(s) > 0 {
-		l := len(s) - 1
-		s = s[0:l]
-	}
-	return s
+	return strings.TrimSuffix(b.String(), ",")
 }
 
 // printInNames prints names for input parameters
@@ -450,6 +1194,9 @@ func printInNames(params []*param) string {
 		}
 		b.WriteString(" ")
 		b.WriteString(p.InName)
+		if p.Variadic {
+			b.WriteString("...")
+		}
 		b.WriteString(",")
 	}
 	s := b.String()
@@ -476,11 +1223,7 @@ func paramSlice(params []*param) string {
 		b.WriteString(" ")
 		b.WriteString(",")
 	}
-	s := b.String()
-	if len(s) > 0 {
-		l := len(s) - 1
-		s = s[0:l]
-	}
+	s := strings.TrimSuffix(b.String(), ",")
 	s = s + "}"
 	return s
 }
@@ -500,13 +1243,15 @@ func receiver(fn *funcInfo) string {
 	return "v "
 }
 
-// printImports prints out all the required imports for a generated mock
-func printImports(tmap map[reflect.Type]*typeInfo) string {
+// printImports prints out all the required imports for a generated mock into the package with import path pkg
+func printImports(tmap map[reflect.Type]*typeInfo, pkg string) string {
 	b := strings.Builder{}
+	seen := map[string]bool{"": true, pkg: true}
 	for _, info := range tmap {
 		for i := 0; i < len(info.Imports); i++ {
 			imp := info.Imports[i]
-			if !strings.Contains(b.String(), imp) && !strings.HasSuffix(imp, info.Pkg) {
+			if !seen[imp] {
+				seen[imp] = true
 				b.WriteRune('"')
 				b.WriteString(imp)
 				b.WriteRune('"')
@@ -528,14 +1273,7 @@ func populateFields(info *typeInfo, t reflect.Type) []*fieldInfo {
 		if f.Name == "DEP_" {
 			continue
 		}
-		t2 := f.Type
-		if t2.PkgPath() != "" {
-			info.Imports = append(info.Imports, t2.PkgPath())
-		}
-		if t2.Kind() == reflect.Ptr {
-			t21 := t2.Elem()
-			info.Imports = append(info.Imports, t21.PkgPath())
-		}
+		info.Imports = pkgPaths(f.Type, info.Imports)
 		fi := fieldInfo{Name: f.Name, Typ: f.Type, TName: f.Type.String(), StructField: f}
 		fields = append(fields, &fi)
 	}
@@ -552,19 +1290,13 @@ func depFields(info *typeInfo) {
 	}
 }
 
-// printFields prints all the fields of a generated mock
-func printFields(fields []*fieldInfo) string {
+// printFields prints all the fields of a generated mock into the package with import path pkg
+func printFields(fields []*fieldInfo, pkg string) string {
 	var b strings.Builder
 	for _, f := range fields {
-		fmt.Fprintf(&b, "%s %s\n", f.Name, f.TName)
+		fmt.Fprintf(&b, "%s %s\n", f.Name, typeName(f.Typ, pkg))
 	}
-	s := b.String()
-	a := bpath
-	fmt.Println(a)
-	if "" != bpath && strings.Contains(s, bpath) {
-		s = strings.Replace(s, bpath+".", "", -1)
-	}
-	return s
+	return b.String()
 }
 
 func fnExists(t *typeInfo, name string) bool {
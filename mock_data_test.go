@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -96,3 +97,80 @@ type L3 struct {
 func (l L3) LM3(i int, f float32) string {
 	return "return from LM3"
 }
+
+type Cyc1 struct {
+	C2 *Cyc2 `_fuse:"cyc2"`
+}
+
+type Cyc2 struct {
+	C3 *Cyc3 `_fuse:"cyc3"`
+}
+
+type Cyc3 struct {
+	C1    *Cyc1       `_fuse:"cyc1"`
+	DEPS_ interface{} `_deps:"l3"`
+}
+
+type Store struct {
+}
+
+func (s *Store) Load(id string) (string, error) {
+	return "loaded " + id, nil
+}
+
+func (s *Store) Fetch(ctx context.Context, id string) (string, error) {
+	return "fetched " + id, ctx.Err()
+}
+
+// Emb embeds a struct and an interface, their methods are promoted to it
+type Emb struct {
+	L2
+	Il3
+	Name string
+}
+
+func (e *Emb) Own(s string) string {
+	return e.Name + s
+}
+
+// Clock receives its dependencies as funcs
+type Clock struct {
+	Now   func() time.Time                                    `_fuse:"now"`
+	Fetch func(ctx context.Context, id string) (*Svc3, error) `_fake:""`
+	Log   func(msg string)                                    `_fake:""`
+	Trace func(format string, args ...interface{})            `_fake:""`
+	Sleep func(d time.Duration)
+}
+
+func (c *Clock) Stamp(id string) string {
+	c.Log(id)
+	return id + "@" + c.Now().String()
+}
+
+type Message struct {
+	Name string
+}
+
+// Bus returns channels and takes callbacks
+type Bus struct {
+}
+
+func (b *Bus) Subscribe(topic string) (<-chan Message, error) {
+	return make(chan Message), nil
+}
+
+func (b *Bus) Walk(fn func(m Message) error) error {
+	return fn(Message{Name: "real"})
+}
+
+func (b *Bus) Each(fn func(i int, s string)) {
+	fn(0, "real")
+}
+
+func (b *Bus) Fill(dst []byte) int {
+	return copy(dst, "real")
+}
+
+func (b *Bus) Publish(topic string, args ...interface{}) error {
+	return nil
+}
@@ -1,10 +1,15 @@
 package mock
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rvauradkar1/fuse"
 )
@@ -16,8 +21,8 @@ func Test_pop(t *testing.T) {
 		t.Errorf("length of populateFields should have been %d, but was %d", 7, len(info.Fields))
 	}
 	fmt.Println(len(info.Imports))
-	if len(info.Imports) != 14 {
-		t.Errorf("length of imports should have been %d, but was %d", 14, len(info.Imports))
+	if len(info.Imports) != 16 {
+		t.Errorf("length of imports should have been %d, but was %d", 16, len(info.Imports))
 	}
 	fmt.Println(len(info.Funcs))
 	if len(info.Funcs) != 3 {
@@ -67,7 +72,7 @@ func Test_pkg(t *testing.T) {
 
 func Test_printOutParams(t *testing.T) {
 	info := populateInfo(Component{Instance: &L1{}, Basepath: "./lvl1"})
-	s := printOutParams(info.Funcs[0].Params)
+	s := printOutParams(info.Funcs[0].Params, runtimePath)
 	if s != "(string,*int)" {
 		t.Errorf("should have been '%s', but was '%s'", "(string,*int)", s)
 	}
@@ -75,13 +80,29 @@ func Test_printOutParams(t *testing.T) {
 
 func Test_printInParams(t *testing.T) {
 	info := populateInfo(Component{Instance: &L1{}, Basepath: "./lvl1"})
-	s := printInParams(info.Funcs[0].Params)
-	if s != "i1 int,f2 float32" {
-		t.Errorf("should have been '%s', but was '%s'", "i1 int,f2 float32", s)
+	s := printInParams(info.Funcs[0].Params, runtimePath, true)
+	if s != "a1 int,a2 float32" {
+		t.Errorf("should have been '%s', but was '%s'", "a1 int,a2 float32", s)
 	}
-	s = printInParams(info.Funcs[2].Params)
-	if s != "pf1 *float32" {
-		t.Errorf("should have been '%s', but was '%s'", "pf1 *float32", s)
+	s = printInParams(info.Funcs[2].Params, runtimePath, true)
+	if s != "a1 *float32" {
+		t.Errorf("should have been '%s', but was '%s'", "a1 *float32", s)
+	}
+	// the last input is the last parameter when there are no outputs
+	s = printInParams(info.Funcs[0].Params[:2], runtimePath, true)
+	if s != "a1 int" {
+		t.Errorf("should have been '%s', but was '%s'", "a1 int", s)
+	}
+	// slices and variadic inputs are named by position as well
+	info = populateInfo(Component{Instance: &Bus{}})
+	for _, c := range []struct {
+		fn     int
+		spread bool
+		want   string
+	}{{1, true, "a1 []uint8"}, {2, true, "a1 string,a2 ...interface {}"}, {2, false, "a1 string,a2 []interface {}"}} {
+		if s = printInParams(info.Funcs[c.fn].Params, runtimePath, c.spread); s != c.want {
+			t.Errorf("should have been '%s', but was '%s'", c.want, s)
+		}
 	}
 }
 
@@ -111,17 +132,27 @@ func Test_paramSlice(t *testing.T) {
 	if s != "[]interface{}{p1 ,p2 }" {
 		t.Errorf("should have been '%s', but was '%s'", " p1, p2", s)
 	}
+	s = paramSlice(info.Funcs[0].Params[:1])
+	if s != "[]interface{}{}" {
+		t.Errorf("should have been '%s', but was '%s'", "[]interface{}{}", s)
+	}
 }
 
 func Test_printImports(t *testing.T) {
 	info := populateInfo(Component{Instance: &L1{}, Basepath: "./lvl1"})
 	types := make(map[reflect.Type]*typeInfo)
 	types[reflect.TypeOf(L1{})] = info
-	s := printImports(types)
+	s := printImports(types, runtimePath)
 	fmt.Println(s)
 	if !strings.Contains(s, "time") {
 		t.Errorf("should have contained '%s'", "time")
 	}
+	// imports are matched by path, not by name or substring
+	info = &typeInfo{Imports: []string{"runtime", "time", "example.com/x/gomock", runtimePath, "time"}}
+	s = printImports(map[reflect.Type]*typeInfo{reflect.TypeOf(L1{}): info}, runtimePath)
+	if want := "\"runtime\"\n\"time\"\n\"example.com/x/gomock\"\n"; s != want {
+		t.Errorf("should have been '%s', but was '%s'", want, s)
+	}
 }
 
 func Test_receiver(t *testing.T) {
@@ -143,7 +174,7 @@ func Test_receiver(t *testing.T) {
 
 func Test_printFields(t *testing.T) {
 	info := populateInfo(Component{Instance: &L1{}, Basepath: "./lvl1"})
-	s := printFields(info.Fields)
+	s := printFields(info.Fields, runtimePath)
 	fmt.Println(s)
 	if !strings.Contains(s, "S1 string\ntime time.Duration\nTime2 time.Duration") {
 		t.Errorf("should have contained '%s'", "S1 string\ntime time.Duration\nTime2 time.Duration")
@@ -228,13 +259,117 @@ func Test_gen(t *testing.T) {
 
 */
 
-func Test_register(t *testing.T) {
-	m := New("mock")
+// registered are the components whose mocks are checked in as mocks_test.go, with the options they are generated
+// with
+func registered() ([]fuse.Entry, []Option) {
 	entries := make([]fuse.Entry, 0)
 	entries = append(entries, fuse.Entry{Name: "OrdCtrl", Instance: &L1{}})
 	entries = append(entries, fuse.Entry{Name: "CartSvc", Instance: &L2{}})
 	entries = append(entries, fuse.Entry{Name: "AuthSvc", Instance: &L3{}})
+	entries = append(entries, fuse.Entry{Name: "Clock", Instance: &Clock{}})
+	entries = append(entries, fuse.Entry{Name: "Bus", Instance: &Bus{}})
+	return entries, []Option{WithSpy("CartSvc"), WithFixture("OrdCtrl")}
+}
+
+func Test_register(t *testing.T) {
+	entries, opts := registered()
+	m := New("mock", opts...)
 	errors := m.Register(entries)
 	fmt.Println("errors = ", errors)
 	m.Generate()
 }
+
+// compile generates the mocks of the registered components with opts and vets the package with them in place of
+// mocks_test.go, it is skipped where the package cannot be built
+func compile(t *testing.T, opts ...Option) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	sink := func(path string, src []byte) error {
+		files[path] = string(src)
+		return nil
+	}
+	entries, base := registered()
+	m := New("mock", append(append(base, WithOutput(sink), WithReporter(Silent)), opts...)...)
+	if errs := m.Register(entries); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	if errs := m.Generate(); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	if testing.Short() {
+		t.Skip("compiling generated mocks in short mode")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	if out, err := exec.Command(gobin, "list", ".").CombinedOutput(); err != nil {
+		t.Skipf("package cannot be built here: %s", out)
+	}
+	wd, _ := os.Getwd()
+	dir := t.TempDir()
+	replace := map[string]string{filepath.Join(wd, "mocks_test.go"): filepath.Join(dir, "mocks_test.go")}
+	if err := os.WriteFile(replace[filepath.Join(wd, "mocks_test.go")], []byte("package mock\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for path, src := range files {
+		replace[path] = filepath.Join(dir, filepath.Base(path))
+		if err := os.WriteFile(replace[path], []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	overlay, _ := json.Marshal(map[string]interface{}{"Replace": replace})
+	if err := os.WriteFile(filepath.Join(dir, "overlay.json"), overlay, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(gobin, "vet", "-overlay", filepath.Join(dir, "overlay.json"), ".").CombinedOutput(); err != nil {
+		t.Errorf("generated mocks should have compiled, but got %s", out)
+	}
+	return files
+}
+
+func Test_fileNames(t *testing.T) {
+	files := compile(t, WithFileName("mock_%s_test.go"))
+	if len(files) != 5 {
+		t.Fatalf("number of files should have been %d, but was %d", 5, len(files))
+	}
+	all := ""
+	for _, src := range files {
+		all += src
+	}
+	for _, decl := range []string{"func NumCalls(", "type Defaults struct", "type MockL2 struct", "type LM3 func("} {
+		if n := strings.Count(all, decl); n != 1 {
+			t.Errorf("%s should have been declared %d time, but was %d", decl, 1, n)
+		}
+	}
+}
+
+func Test_variadic(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := &MockBus{}
+	want := fmt.Errorf("full")
+	m.PublishReturnsWhen("t", []interface{}{1, "x"}, want)
+	if err := m.Publish("t", 1, "x"); err != want {
+		t.Errorf("should have been %v, but was %v", want, err)
+	}
+	if calls := m.PublishCalls(); len(calls) != 1 || calls[0].A1 != "t" || len(calls[0].A2) != 2 {
+		t.Errorf("the variadic arguments should have been recorded as a slice, but were %+v", calls)
+	}
+}
+
+func Test_fakes(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	now := time.Unix(0, 0)
+	MockClock_FakeNow = func() time.Time { return now }
+	m := NewMockClock()
+	if m.Now() != now || m.Trace != nil {
+		t.Errorf("Now should have been wired to its fake and the variadic Trace left nil")
+	}
+	real := &Clock{Now: m.FakeNow, Log: m.FakeLog}
+	if s := real.Stamp("id"); s != "id@"+now.String() {
+		t.Errorf("real component should have used the fakes, but returned %s", s)
+	}
+	if calls := m.FakeLogCalls(); len(calls) != 1 || calls[0].A1 != "id" || m.FakeNowCallCount() != 2 {
+		t.Errorf("calls of the fakes should have been recorded, but were %+v", calls)
+	}
+}
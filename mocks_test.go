@@ -1,21 +1,58 @@
 package mock
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
 	"time"
 )
 
 // Start of method calls and parameter capture
 var stats = make(map[string]*funcCalls, 0)
 
+// calls holds every call of every mock in order
+var calls = make([]*CallInfo, 0)
+
+var statsMu sync.Mutex
+
+// seq counts the calls captured so far
+var seq int64
+
+// marks are the seq of tests when they attached, by test name
+var marks = make(map[string]int64)
+
+// ArgSnapshots selects the methods whose arguments are deep copied when captured, none by default
+var ArgSnapshots = &Snapshots{}
+
 type funcCalls struct {
 	Count  int
 	Params [][]interface{}
+	Calls  []*CallInfo
 }
 
+// CallInfo is the record of one call of a mock method
 type CallInfo struct {
-	Ok     bool
-	Name   string
-	Params []interface{}
+	// Ok is set when the call returned normally
+	Ok bool
+	// Name is the key of the method, e.g. MockL1_LM1
+	Name    string
+	Params  []interface{}
+	Results []interface{}
+	// Panic is the value the call panicked with
+	Panic interface{}
+	Time  time.Time
+	// Goroutine is the id of the goroutine the call was made on
+	Goroutine int64
+	// Caller is the file:line the mock was called from
+	Caller string
+	// Seq numbers the calls of all mocks in order, from 1
+	Seq int64
 }
 
 type Params []interface{}
@@ -37,7 +74,38 @@ func CallParams(name string) []Params {
 	return []Params{}
 }
 
-func capture(key string, params []interface{}) {
+// Calls returns the records of the calls of a method in order
+func Calls(name string) []CallInfo {
+	call := forCall(name)
+	return copyCalls(call.Calls)
+}
+
+// AllCalls returns the records of the calls of all mocks in order
+func AllCalls() []CallInfo {
+	statsMu.Lock()
+	all := calls
+	statsMu.Unlock()
+	return copyCalls(all)
+}
+
+func copyCalls(records []*CallInfo) []CallInfo {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	infos := make([]CallInfo, 0, len(records))
+	for _, c := range records {
+		infos = append(infos, *c)
+	}
+	return infos
+}
+
+func capture(key string, params []interface{}) *CallInfo {
+	params = ArgSnapshots.Copy(key, params)
+	call := &CallInfo{Name: key, Params: params, Time: time.Now(), Goroutine: goroutine()}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		call.Caller = file + ":" + strconv.Itoa(line)
+	}
+	statsMu.Lock()
+	defer statsMu.Unlock()
 	val, ok := stats[key]
 	if !ok {
 		val = &funcCalls{}
@@ -47,73 +115,1853 @@ func capture(key string, params []interface{}) {
 	}
 	val.Count++
 	val.Params = append(val.Params, params)
+	val.Calls = append(val.Calls, call)
+	seq++
+	call.Seq = seq
+	calls = append(calls, call)
+	return call
+}
+
+// done records the results of a call, or its panic which is then resumed
+func (c *CallInfo) done(p interface{}, results ...interface{}) {
+	statsMu.Lock()
+	c.Ok = p == nil
+	c.Panic = p
+	c.Results = results
+	statsMu.Unlock()
+	if p != nil {
+		panic(p)
+	}
+}
+
+func goroutine() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	fields := strings.Fields(strings.TrimPrefix(string(buf), "goroutine "))
+	id, _ := strconv.ParseInt(fields[0], 10, 64)
+	return id
+}
+
+func init() {
+	// -update rewrites the golden files of AssertCallLog, unless the test package defines the flag itself
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "rewrite golden files")
+	}
+	resets = append(resets, resetCalls)
+}
+
+// resetCalls forgets the calls recorded so far
+func resetCalls() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats = make(map[string]*funcCalls, 0)
+	calls = make([]*CallInfo, 0)
+}
+
+// AssertCallLog compares the log of the calls made during t in order with the golden file testdata/<name>.golden,
+// running the tests with -update rewrites it. Calls are logged from Attach of t or of a parent test, or else since
+// the mocks were last reset
+func AssertCallLog(t testing.TB, name string) {
+	t.Helper()
+	AssertGolden(t, name, CallLog(loggedCalls(since(t))))
+}
+
+// Attach marks the start of the calls logged for t and, when t has failed, logs a table of the calls per mock made
+// since. It leaves the mocks as they are, register ResetMocks with t.Cleanup to reset them
+func Attach(t testing.TB) {
+	statsMu.Lock()
+	mark := seq
+	marks[t.Name()] = mark
+	statsMu.Unlock()
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("calls of mocks:\n%s", CallTable(loggedCalls(mark)))
+		}
+		statsMu.Lock()
+		delete(marks, t.Name())
+		statsMu.Unlock()
+	})
+}
+
+// since returns the seq t or its closest parent attached at, 0 when none did
+func since(t testing.TB) int64 {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	name := t.Name()
+	for {
+		if mark, ok := marks[name]; ok {
+			return mark
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return 0
+		}
+		name = name[:i]
+	}
+}
 
+// loggedCalls returns the calls after the mark in order
+func loggedCalls(mark int64) []LoggedCall {
+	log := make([]LoggedCall, 0)
+	for _, c := range AllCalls() {
+		if c.Seq > mark {
+			log = append(log, LoggedCall{Name: c.Name, Args: c.Params, Results: c.Results, Panic: c.Panic, Caller: c.Caller})
+		}
+	}
+	return log
 }
 
 func forCall(key string) funcCalls {
+	statsMu.Lock()
+	defer statsMu.Unlock()
 	if val, ok := stats[key]; ok {
 		return *val
 	}
 	return funcCalls{}
 }
 
+// returns holds the results of a method configured per call, in sequence or per arguments. Calls are counted
+// from the first configured result, base is the number of calls made before
+type returns struct {
+	outs     int
+	started  bool
+	base     int
+	onCall   map[int][]interface{}
+	sequence [][]interface{}
+	when     []whenReturns
+}
+
+type whenReturns struct {
+	params  []interface{}
+	results []interface{}
+}
+
+// results finds the results of the current call of key, by arguments first and then by call number.
+// It returns nil when none are configured, and fails when the configured sequence is exhausted
+func (r *returns) results(d *Defaults, key string, params []interface{}) []interface{} {
+	for _, w := range r.when {
+		if reflect.DeepEqual(w.params, params) {
+			return w.results
+		}
+	}
+	n := NumCalls(key) - 1 - r.base
+	if res, ok := r.onCall[n]; ok {
+		return res
+	}
+	if n < len(r.sequence) {
+		return r.sequence[n]
+	}
+	if len(r.sequence) == 0 {
+		return nil
+	}
+	if d == nil {
+		d = UnstubbedDefaults
+	}
+	msg := fmt.Sprintf("%s call %d with %v exhausted the sequence of %d results", key, n, params, len(r.sequence))
+	if d.Fail == nil {
+		panic(msg)
+	}
+	d.Fail("%s", msg)
+	return make([]interface{}, r.outs)
+}
+
+// start counts calls of key from now on, unless results were configured before
+func (r *returns) start(key string) {
+	if !r.started {
+		r.started = true
+		r.base = NumCalls(key)
+	}
+}
+
+// reset forgets the configured results
+func (r *returns) reset() {
+	*r = returns{outs: r.outs}
+}
+
 // End of method calls and parameter capture
 
-// Begin of mock for L2 and its methods
-type MockL2 struct {
-	s    string
-	time time.Duration
-	Il3  Il3
+// Start of defaults for methods without a stub
+// Defaults decides what mocks return from methods without a stub, zero values unless configured
+type Defaults struct {
+	// Fail, when set, is called with the method and its arguments, e.g. t.Fatalf
+	Fail func(format string, args ...interface{})
+	// Values are returned for results of their type
+	Values map[reflect.Type]interface{}
 }
 
-type LM21 func(i1 int, f2 float32) string
+// UnstubbedDefaults applies to mocks without Defaults of their own
+var UnstubbedDefaults = &Defaults{}
 
-var MockL2_LM21 LM21
+// resets clear the stubs of mock methods and what is configured for them, and the recorded calls
+var resets = make([]func(), 0)
 
-func (v MockL2) LM21(i1 int, f2 float32) string {
-	capture("MockL2_LM21", []interface{}{i1, f2})
-	return MockL2_LM21(i1, f2)
+// ResetMocks clears the stubs of every mock method and what is configured for them, e.g. results, faults and
+// latencies, and forgets the recorded calls. Register it with t.Cleanup
+func ResetMocks() {
+	for _, reset := range resets {
+		reset()
+	}
 }
 
-// End of mock for L2 and its methods
+// Return sets the value returned for results of the type ptr points to, e.g. Return((*error)(nil), errSentinel).
+// It panics when ptr is not a pointer or value is not assignable to the type
+func (d *Defaults) Return(ptr interface{}, value interface{}) *Defaults {
+	t := reflect.TypeOf(ptr)
+	if t == nil || t.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("Defaults.Return: %T should have been a pointer to the type of the results", ptr))
+	}
+	if value != nil && !reflect.TypeOf(value).AssignableTo(t.Elem()) {
+		panic(fmt.Sprintf("Defaults.Return: %T is not assignable to results of type %s", value, t.Elem()))
+	}
+	if d.Values == nil {
+		d.Values = make(map[reflect.Type]interface{})
+	}
+	d.Values[t.Elem()] = value
+	return d
+}
 
-// Begin of mock for L1 and its methods
-type MockL1 struct {
-	s     string
-	S1    string
-	time  time.Duration
-	Time2 time.Duration
-	L2    L2
-	Il2   Il2
-	PL2   *L2
-	DEPS_ interface{}
+func unstubbed(d *Defaults, key string, params []interface{}, results ...interface{}) {
+	if d == nil {
+		d = UnstubbedDefaults
+	}
+	if d.Fail != nil {
+		d.Fail("%s called with %v, but has no stub", key, params)
+	}
+	for _, r := range results {
+		v := reflect.ValueOf(r).Elem()
+		if val, ok := d.Values[v.Type()]; ok && val != nil {
+			v.Set(reflect.ValueOf(val))
+		}
+	}
+}
+
+// failed reports a failed call through d.Fail, or panics when it is not set
+func failed(d *Defaults, format string, args ...interface{}) {
+	if d == nil {
+		d = UnstubbedDefaults
+	}
+	if d.Fail == nil {
+		panic(fmt.Sprintf(format, args...))
+	}
+	d.Fail(format, args...)
 }
 
-type LM1 func(i1 int, f2 float32) (string, *int)
+// End of defaults for methods without a stub
 
-var MockL1_LM1 LM1
+// Begin of mock for Bus and its methods
+type MockBus struct {
+	// Defaults overrides UnstubbedDefaults for this mock
+	Defaults *Defaults
+}
 
-func (v MockL1) LM1(i1 int, f2 float32) (string, *int) {
-	capture("MockL1_LM1", []interface{}{i1, f2})
-	return MockL1_LM1(i1, f2)
+// init provides MockBus as the mock of Bus to Swap
+func init() {
+	RegisterMockFactory("github.com/rvauradkar1/mockgen", "Bus", func() interface{} {
+		return &MockBus{}
+	})
 }
 
-type LM2 func(t1 time.Duration, f2 float32) (string, time.Duration)
+type Each func(a1 func(int, string))
 
-var MockL1_LM2 LM2
+var MockBus_Each Each
 
-func (p *MockL1) LM2(t1 time.Duration, f2 float32) (string, time.Duration) {
-	capture("MockL1_LM2", []interface{}{t1, f2})
-	return MockL1_LM2(t1, f2)
+// MockBus_Each_Faults are injected into calls of Each before the stub is invoked
+var MockBus_Each_Faults Faults
+
+// MockBus_Each_A1_Callbacks are the invocations of the argument a1 of every call of Each
+var MockBus_Each_A1_Callbacks Callbacks
+
+// EachInvokesA1 makes calls of Each invoke their argument a1 with the given arguments,
+// after the invocations configured before
+func (p *MockBus) EachInvokesA1(a0 int, a1 string) {
+	MockBus_Each_A1_Callbacks.Add(a0, a1)
+}
+func (p *MockBus) Each(a1 func(int, string)) {
+	call := capture("MockBus_Each", []interface{}{a1})
+	defer func() {
+		call.done(recover())
+	}()
+	if MockBus_Each_Faults.Inject([]interface{}{a1}, nil) {
+		return
+	}
+	if err := MockBus_Each_A1_Callbacks.Invoke(a1); err != nil {
+		failed(p.Defaults, "MockBus_Each %s", err)
+	}
+	if MockBus_Each == nil {
+		unstubbed(p.Defaults, "MockBus_Each", []interface{}{a1})
+		return
+	}
+	MockBus_Each(a1)
 }
 
-type LM3 func(pf1 *float32) (string, time.Duration)
+// MockBusEachCall is a recorded call of Each, results are zero when the call did not return
+type MockBusEachCall struct {
+	A1 func(int, string)
+}
 
-var MockL1_LM3 LM3
+// EachCalls returns the recorded calls of Each in order
+func (p *MockBus) EachCalls() []MockBusEachCall {
+	calls := make([]MockBusEachCall, 0)
+	for _, c := range Calls("MockBus_Each") {
+		call := MockBusEachCall{}
+		call.A1, _ = c.Params[0].(func(int, string))
+		calls = append(calls, call)
+	}
+	return calls
+}
 
-func (p *MockL1) LM3(pf1 *float32) (string, time.Duration) {
-	capture("MockL1_LM3", []interface{}{pf1})
-	return MockL1_LM3(pf1)
+// EachCallCount returns the number of calls of Each
+func (p *MockBus) EachCallCount() int {
+	return NumCalls("MockBus_Each")
 }
 
-// End of mock for L1 and its methods
+// MockBus_Each_Reset clears the stub of Each and what is configured for it
+func MockBus_Each_Reset() {
+	MockBus_Each = nil
+	MockBus_Each_Faults.Reset()
+	MockBus_Each_A1_Callbacks.Reset()
+}
+
+func init() {
+	resets = append(resets, MockBus_Each_Reset)
+}
+
+type Fill func(a1 []uint8) int
+
+var MockBus_Fill Fill
+
+// MockBus_Fill_Faults are injected into calls of Fill before the stub is invoked
+var MockBus_Fill_Faults Faults
+
+// MockBus_Fill_ArgWrites are written into the arguments of calls of Fill before the stub is invoked
+var MockBus_Fill_ArgWrites ArgWrites
+
+// FillSetsArg writes value into the nth argument of calls of Fill, counting from 0
+func (p *MockBus) FillSetsArg(n int, value interface{}) {
+	MockBus_Fill_ArgWrites.Set(n, value)
+}
+func (p *MockBus) Fill(a1 []uint8) (ret0 int) {
+	call := capture("MockBus_Fill", []interface{}{a1})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockBus_Fill_Faults.Inject([]interface{}{a1}, nil) {
+		return
+	}
+	if err := MockBus_Fill_ArgWrites.Apply([]interface{}{a1}); err != nil {
+		failed(p.Defaults, "MockBus_Fill %s", err)
+	}
+	if MockBus_Fill == nil {
+		unstubbed(p.Defaults, "MockBus_Fill", []interface{}{a1}, &ret0)
+		return
+	}
+	return MockBus_Fill(a1)
+}
+
+// MockBusFillCall is a recorded call of Fill, results are zero when the call did not return
+type MockBusFillCall struct {
+	A1   []uint8
+	Ret0 int
+}
+
+// FillCalls returns the recorded calls of Fill in order
+func (p *MockBus) FillCalls() []MockBusFillCall {
+	calls := make([]MockBusFillCall, 0)
+	for _, c := range Calls("MockBus_Fill") {
+		call := MockBusFillCall{}
+		call.A1, _ = c.Params[0].([]uint8)
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(int)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// FillCallCount returns the number of calls of Fill
+func (p *MockBus) FillCallCount() int {
+	return NumCalls("MockBus_Fill")
+}
+
+var MockBus_Fill_Returns = &returns{outs: 1}
+
+// MockBus_Fill_Results are the results of one call of Fill
+type MockBus_Fill_Results struct {
+	R0 int
+}
+
+// FillReturnsOnCall sets the results of the nth call of Fill, counting from 0
+func (p *MockBus) FillReturnsOnCall(n int, ret0 int) {
+	if MockBus_Fill_Returns.onCall == nil {
+		MockBus_Fill_Returns.onCall = make(map[int][]interface{})
+	}
+	MockBus_Fill_Returns.onCall[n] = []interface{}{ret0}
+	p.useFillReturns()
+}
+
+// FillReturnsSequence sets the results of successive calls of Fill, calls beyond the sequence fail
+func (p *MockBus) FillReturnsSequence(results ...MockBus_Fill_Results) {
+	for _, r := range results {
+		MockBus_Fill_Returns.sequence = append(MockBus_Fill_Returns.sequence, []interface{}{r.R0})
+	}
+	p.useFillReturns()
+}
+
+// FillReturnsWhen sets the results of calls of Fill with the given arguments
+func (p *MockBus) FillReturnsWhen(a1 []uint8, ret0 int) {
+	w := whenReturns{params: []interface{}{a1}, results: []interface{}{ret0}}
+	MockBus_Fill_Returns.when = append(MockBus_Fill_Returns.when, w)
+	p.useFillReturns()
+}
+
+func (p *MockBus) useFillReturns() {
+	MockBus_Fill_Returns.start("MockBus_Fill")
+	MockBus_Fill = func(a1 []uint8) int {
+		res := MockBus_Fill_Returns.results(p.Defaults, "MockBus_Fill", []interface{}{a1})
+		if res == nil {
+			var ret0 int
+			unstubbed(p.Defaults, "MockBus_Fill", []interface{}{a1}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(int)
+		return ret0
+	}
+}
+
+// MockBus_Fill_Reset clears the stub of Fill and what is configured for it
+func MockBus_Fill_Reset() {
+	MockBus_Fill = nil
+	MockBus_Fill_Faults.Reset()
+	MockBus_Fill_ArgWrites.Reset()
+	MockBus_Fill_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockBus_Fill_Reset)
+}
+
+type Publish func(a1 string, a2 ...interface{}) error
+
+var MockBus_Publish Publish
+
+// MockBus_Publish_Faults are injected into calls of Publish before the stub is invoked
+var MockBus_Publish_Faults Faults
+
+// MockBus_Publish_ArgWrites are written into the arguments of calls of Publish before the stub is invoked
+var MockBus_Publish_ArgWrites ArgWrites
+
+// PublishSetsArg writes value into the nth argument of calls of Publish, counting from 0
+func (p *MockBus) PublishSetsArg(n int, value interface{}) {
+	MockBus_Publish_ArgWrites.Set(n, value)
+}
+func (p *MockBus) Publish(a1 string, a2 ...interface{}) (ret0 error) {
+	call := capture("MockBus_Publish", []interface{}{a1, a2})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockBus_Publish_Faults.Inject([]interface{}{a1, a2}, &ret0) {
+		return
+	}
+	if err := MockBus_Publish_ArgWrites.Apply([]interface{}{a1, a2}); err != nil {
+		failed(p.Defaults, "MockBus_Publish %s", err)
+	}
+	if MockBus_Publish == nil {
+		unstubbed(p.Defaults, "MockBus_Publish", []interface{}{a1, a2}, &ret0)
+		return
+	}
+	return MockBus_Publish(a1, a2...)
+}
+
+// MockBusPublishCall is a recorded call of Publish, results are zero when the call did not return
+type MockBusPublishCall struct {
+	A1   string
+	A2   []interface{}
+	Ret0 error
+}
+
+// PublishCalls returns the recorded calls of Publish in order
+func (p *MockBus) PublishCalls() []MockBusPublishCall {
+	calls := make([]MockBusPublishCall, 0)
+	for _, c := range Calls("MockBus_Publish") {
+		call := MockBusPublishCall{}
+		call.A1, _ = c.Params[0].(string)
+		call.A2, _ = c.Params[1].([]interface{})
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(error)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// PublishCallCount returns the number of calls of Publish
+func (p *MockBus) PublishCallCount() int {
+	return NumCalls("MockBus_Publish")
+}
+
+var MockBus_Publish_Returns = &returns{outs: 1}
+
+// MockBus_Publish_Results are the results of one call of Publish
+type MockBus_Publish_Results struct {
+	R0 error
+}
+
+// PublishReturnsOnCall sets the results of the nth call of Publish, counting from 0
+func (p *MockBus) PublishReturnsOnCall(n int, ret0 error) {
+	if MockBus_Publish_Returns.onCall == nil {
+		MockBus_Publish_Returns.onCall = make(map[int][]interface{})
+	}
+	MockBus_Publish_Returns.onCall[n] = []interface{}{ret0}
+	p.usePublishReturns()
+}
+
+// PublishReturnsSequence sets the results of successive calls of Publish, calls beyond the sequence fail
+func (p *MockBus) PublishReturnsSequence(results ...MockBus_Publish_Results) {
+	for _, r := range results {
+		MockBus_Publish_Returns.sequence = append(MockBus_Publish_Returns.sequence, []interface{}{r.R0})
+	}
+	p.usePublishReturns()
+}
+
+// PublishReturnsWhen sets the results of calls of Publish with the given arguments
+func (p *MockBus) PublishReturnsWhen(a1 string, a2 []interface{}, ret0 error) {
+	w := whenReturns{params: []interface{}{a1, a2}, results: []interface{}{ret0}}
+	MockBus_Publish_Returns.when = append(MockBus_Publish_Returns.when, w)
+	p.usePublishReturns()
+}
+
+func (p *MockBus) usePublishReturns() {
+	MockBus_Publish_Returns.start("MockBus_Publish")
+	MockBus_Publish = func(a1 string, a2 ...interface{}) error {
+		res := MockBus_Publish_Returns.results(p.Defaults, "MockBus_Publish", []interface{}{a1, a2})
+		if res == nil {
+			var ret0 error
+			unstubbed(p.Defaults, "MockBus_Publish", []interface{}{a1, a2}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(error)
+		return ret0
+	}
+}
+
+// MockBus_Publish_Reset clears the stub of Publish and what is configured for it
+func MockBus_Publish_Reset() {
+	MockBus_Publish = nil
+	MockBus_Publish_Faults.Reset()
+	MockBus_Publish_ArgWrites.Reset()
+	MockBus_Publish_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockBus_Publish_Reset)
+}
+
+type Subscribe func(a1 string) (<-chan Message, error)
+
+var MockBus_Subscribe Subscribe
+
+// MockBus_Subscribe_Faults are injected into calls of Subscribe before the stub is invoked
+var MockBus_Subscribe_Faults Faults
+
+// MockBus_Subscribe_Feed is the channel Subscribe returns as ret0 when it has no stub and values were sent
+var MockBus_Subscribe_Feed = NewFeed((chan Message)(nil))
+
+// SubscribeSend queues values, delivered in order into the channel Subscribe returns
+func (p *MockBus) SubscribeSend(values ...Message) {
+	for _, v := range values {
+		MockBus_Subscribe_Feed.Send(v)
+	}
+}
+
+// SubscribeClose closes the channel Subscribe returns once the queued values are delivered
+func (p *MockBus) SubscribeClose() {
+	MockBus_Subscribe_Feed.Close()
+}
+func (p *MockBus) Subscribe(a1 string) (ret0 <-chan Message, ret1 error) {
+	call := capture("MockBus_Subscribe", []interface{}{a1})
+	defer func() {
+		call.done(recover(), ret0, ret1)
+	}()
+	if MockBus_Subscribe_Faults.Inject([]interface{}{a1}, &ret1) {
+		return
+	}
+	if MockBus_Subscribe == nil && MockBus_Subscribe_Feed.Fed() {
+		ret0 = MockBus_Subscribe_Feed.Chan().(chan Message)
+		return
+	}
+	if MockBus_Subscribe == nil {
+		unstubbed(p.Defaults, "MockBus_Subscribe", []interface{}{a1}, &ret0, &ret1)
+		return
+	}
+	return MockBus_Subscribe(a1)
+}
+
+// MockBusSubscribeCall is a recorded call of Subscribe, results are zero when the call did not return
+type MockBusSubscribeCall struct {
+	A1   string
+	Ret0 <-chan Message
+	Ret1 error
+}
+
+// SubscribeCalls returns the recorded calls of Subscribe in order
+func (p *MockBus) SubscribeCalls() []MockBusSubscribeCall {
+	calls := make([]MockBusSubscribeCall, 0)
+	for _, c := range Calls("MockBus_Subscribe") {
+		call := MockBusSubscribeCall{}
+		call.A1, _ = c.Params[0].(string)
+		if len(c.Results) == 2 {
+			call.Ret0, _ = c.Results[0].(<-chan Message)
+			call.Ret1, _ = c.Results[1].(error)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// SubscribeCallCount returns the number of calls of Subscribe
+func (p *MockBus) SubscribeCallCount() int {
+	return NumCalls("MockBus_Subscribe")
+}
+
+var MockBus_Subscribe_Returns = &returns{outs: 2}
+
+// MockBus_Subscribe_Results are the results of one call of Subscribe
+type MockBus_Subscribe_Results struct {
+	R0 <-chan Message
+	R1 error
+}
+
+// SubscribeReturnsOnCall sets the results of the nth call of Subscribe, counting from 0
+func (p *MockBus) SubscribeReturnsOnCall(n int, ret0 <-chan Message, ret1 error) {
+	if MockBus_Subscribe_Returns.onCall == nil {
+		MockBus_Subscribe_Returns.onCall = make(map[int][]interface{})
+	}
+	MockBus_Subscribe_Returns.onCall[n] = []interface{}{ret0, ret1}
+	p.useSubscribeReturns()
+}
+
+// SubscribeReturnsSequence sets the results of successive calls of Subscribe, calls beyond the sequence fail
+func (p *MockBus) SubscribeReturnsSequence(results ...MockBus_Subscribe_Results) {
+	for _, r := range results {
+		MockBus_Subscribe_Returns.sequence = append(MockBus_Subscribe_Returns.sequence, []interface{}{r.R0, r.R1})
+	}
+	p.useSubscribeReturns()
+}
+
+// SubscribeReturnsWhen sets the results of calls of Subscribe with the given arguments
+func (p *MockBus) SubscribeReturnsWhen(a1 string, ret0 <-chan Message, ret1 error) {
+	w := whenReturns{params: []interface{}{a1}, results: []interface{}{ret0, ret1}}
+	MockBus_Subscribe_Returns.when = append(MockBus_Subscribe_Returns.when, w)
+	p.useSubscribeReturns()
+}
+
+func (p *MockBus) useSubscribeReturns() {
+	MockBus_Subscribe_Returns.start("MockBus_Subscribe")
+	MockBus_Subscribe = func(a1 string) (<-chan Message, error) {
+		res := MockBus_Subscribe_Returns.results(p.Defaults, "MockBus_Subscribe", []interface{}{a1})
+		if res == nil {
+			var ret0 <-chan Message
+			var ret1 error
+			unstubbed(p.Defaults, "MockBus_Subscribe", []interface{}{a1}, &ret0, &ret1)
+			return ret0, ret1
+		}
+		ret0, _ := res[0].(<-chan Message)
+		ret1, _ := res[1].(error)
+		return ret0, ret1
+	}
+}
+
+// MockBus_Subscribe_Reset clears the stub of Subscribe and what is configured for it
+func MockBus_Subscribe_Reset() {
+	MockBus_Subscribe = nil
+	MockBus_Subscribe_Faults.Reset()
+	MockBus_Subscribe_Feed.Reset()
+	MockBus_Subscribe_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockBus_Subscribe_Reset)
+}
+
+type Walk func(a1 func(Message) error) error
+
+var MockBus_Walk Walk
+
+// MockBus_Walk_Faults are injected into calls of Walk before the stub is invoked
+var MockBus_Walk_Faults Faults
+
+// MockBus_Walk_A1_Callbacks are the invocations of the argument a1 of every call of Walk
+var MockBus_Walk_A1_Callbacks Callbacks
+
+// WalkInvokesA1 makes calls of Walk invoke their argument a1 with the given arguments,
+// after the invocations configured before
+func (p *MockBus) WalkInvokesA1(a0 Message) {
+	MockBus_Walk_A1_Callbacks.Add(a0)
+}
+func (p *MockBus) Walk(a1 func(Message) error) (ret0 error) {
+	call := capture("MockBus_Walk", []interface{}{a1})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockBus_Walk_Faults.Inject([]interface{}{a1}, &ret0) {
+		return
+	}
+	if err := MockBus_Walk_A1_Callbacks.Invoke(a1); err != nil {
+		ret0 = err
+		return
+	}
+	if MockBus_Walk == nil {
+		unstubbed(p.Defaults, "MockBus_Walk", []interface{}{a1}, &ret0)
+		return
+	}
+	return MockBus_Walk(a1)
+}
+
+// MockBusWalkCall is a recorded call of Walk, results are zero when the call did not return
+type MockBusWalkCall struct {
+	A1   func(Message) error
+	Ret0 error
+}
+
+// WalkCalls returns the recorded calls of Walk in order
+func (p *MockBus) WalkCalls() []MockBusWalkCall {
+	calls := make([]MockBusWalkCall, 0)
+	for _, c := range Calls("MockBus_Walk") {
+		call := MockBusWalkCall{}
+		call.A1, _ = c.Params[0].(func(Message) error)
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(error)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// WalkCallCount returns the number of calls of Walk
+func (p *MockBus) WalkCallCount() int {
+	return NumCalls("MockBus_Walk")
+}
+
+var MockBus_Walk_Returns = &returns{outs: 1}
+
+// MockBus_Walk_Results are the results of one call of Walk
+type MockBus_Walk_Results struct {
+	R0 error
+}
+
+// WalkReturnsOnCall sets the results of the nth call of Walk, counting from 0
+func (p *MockBus) WalkReturnsOnCall(n int, ret0 error) {
+	if MockBus_Walk_Returns.onCall == nil {
+		MockBus_Walk_Returns.onCall = make(map[int][]interface{})
+	}
+	MockBus_Walk_Returns.onCall[n] = []interface{}{ret0}
+	p.useWalkReturns()
+}
+
+// WalkReturnsSequence sets the results of successive calls of Walk, calls beyond the sequence fail
+func (p *MockBus) WalkReturnsSequence(results ...MockBus_Walk_Results) {
+	for _, r := range results {
+		MockBus_Walk_Returns.sequence = append(MockBus_Walk_Returns.sequence, []interface{}{r.R0})
+	}
+	p.useWalkReturns()
+}
+
+// WalkReturnsWhen sets the results of calls of Walk with the given arguments
+func (p *MockBus) WalkReturnsWhen(a1 func(Message) error, ret0 error) {
+	w := whenReturns{params: []interface{}{a1}, results: []interface{}{ret0}}
+	MockBus_Walk_Returns.when = append(MockBus_Walk_Returns.when, w)
+	p.useWalkReturns()
+}
+
+func (p *MockBus) useWalkReturns() {
+	MockBus_Walk_Returns.start("MockBus_Walk")
+	MockBus_Walk = func(a1 func(Message) error) error {
+		res := MockBus_Walk_Returns.results(p.Defaults, "MockBus_Walk", []interface{}{a1})
+		if res == nil {
+			var ret0 error
+			unstubbed(p.Defaults, "MockBus_Walk", []interface{}{a1}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(error)
+		return ret0
+	}
+}
+
+// MockBus_Walk_Reset clears the stub of Walk and what is configured for it
+func MockBus_Walk_Reset() {
+	MockBus_Walk = nil
+	MockBus_Walk_Faults.Reset()
+	MockBus_Walk_A1_Callbacks.Reset()
+	MockBus_Walk_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockBus_Walk_Reset)
+}
+
+// End of mock for Bus and its methods
+
+// Begin of mock for Clock and its methods
+type MockClock struct {
+	Now   func() time.Time
+	Fetch func(context.Context, string) (*Svc3, error)
+	Log   func(string)
+	Trace func(string, ...interface{})
+	Sleep func(time.Duration)
+	// Defaults overrides UnstubbedDefaults for this mock
+	Defaults *Defaults
+}
+
+// NewMockClock returns a mock with its func fields wired to their recording fakes
+func NewMockClock() *MockClock {
+	m := &MockClock{}
+	m.Now = m.FakeNow
+	m.Fetch = m.FakeFetch
+	m.Log = m.FakeLog
+	return m
+}
+
+// init provides MockClock as the mock of Clock to Swap
+func init() {
+	RegisterMockFactory("github.com/rvauradkar1/mockgen", "Clock", func() interface{} {
+		return NewMockClock()
+	})
+}
+
+type Stamp func(a1 string) string
+
+var MockClock_Stamp Stamp
+
+// MockClock_Stamp_Faults are injected into calls of Stamp before the stub is invoked
+var MockClock_Stamp_Faults Faults
+
+func (p *MockClock) Stamp(a1 string) (ret0 string) {
+	call := capture("MockClock_Stamp", []interface{}{a1})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockClock_Stamp_Faults.Inject([]interface{}{a1}, nil) {
+		return
+	}
+	if MockClock_Stamp == nil {
+		unstubbed(p.Defaults, "MockClock_Stamp", []interface{}{a1}, &ret0)
+		return
+	}
+	return MockClock_Stamp(a1)
+}
+
+// MockClockStampCall is a recorded call of Stamp, results are zero when the call did not return
+type MockClockStampCall struct {
+	A1   string
+	Ret0 string
+}
+
+// StampCalls returns the recorded calls of Stamp in order
+func (p *MockClock) StampCalls() []MockClockStampCall {
+	calls := make([]MockClockStampCall, 0)
+	for _, c := range Calls("MockClock_Stamp") {
+		call := MockClockStampCall{}
+		call.A1, _ = c.Params[0].(string)
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(string)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// StampCallCount returns the number of calls of Stamp
+func (p *MockClock) StampCallCount() int {
+	return NumCalls("MockClock_Stamp")
+}
+
+var MockClock_Stamp_Returns = &returns{outs: 1}
+
+// MockClock_Stamp_Results are the results of one call of Stamp
+type MockClock_Stamp_Results struct {
+	R0 string
+}
+
+// StampReturnsOnCall sets the results of the nth call of Stamp, counting from 0
+func (p *MockClock) StampReturnsOnCall(n int, ret0 string) {
+	if MockClock_Stamp_Returns.onCall == nil {
+		MockClock_Stamp_Returns.onCall = make(map[int][]interface{})
+	}
+	MockClock_Stamp_Returns.onCall[n] = []interface{}{ret0}
+	p.useStampReturns()
+}
+
+// StampReturnsSequence sets the results of successive calls of Stamp, calls beyond the sequence fail
+func (p *MockClock) StampReturnsSequence(results ...MockClock_Stamp_Results) {
+	for _, r := range results {
+		MockClock_Stamp_Returns.sequence = append(MockClock_Stamp_Returns.sequence, []interface{}{r.R0})
+	}
+	p.useStampReturns()
+}
+
+// StampReturnsWhen sets the results of calls of Stamp with the given arguments
+func (p *MockClock) StampReturnsWhen(a1 string, ret0 string) {
+	w := whenReturns{params: []interface{}{a1}, results: []interface{}{ret0}}
+	MockClock_Stamp_Returns.when = append(MockClock_Stamp_Returns.when, w)
+	p.useStampReturns()
+}
+
+func (p *MockClock) useStampReturns() {
+	MockClock_Stamp_Returns.start("MockClock_Stamp")
+	MockClock_Stamp = func(a1 string) string {
+		res := MockClock_Stamp_Returns.results(p.Defaults, "MockClock_Stamp", []interface{}{a1})
+		if res == nil {
+			var ret0 string
+			unstubbed(p.Defaults, "MockClock_Stamp", []interface{}{a1}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(string)
+		return ret0
+	}
+}
+
+// MockClock_Stamp_Reset clears the stub of Stamp and what is configured for it
+func MockClock_Stamp_Reset() {
+	MockClock_Stamp = nil
+	MockClock_Stamp_Faults.Reset()
+	MockClock_Stamp_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockClock_Stamp_Reset)
+}
+
+type FakeNow func() time.Time
+
+var MockClock_FakeNow FakeNow
+
+// MockClock_FakeNow_Faults are injected into calls of FakeNow before the stub is invoked
+var MockClock_FakeNow_Faults Faults
+
+func (p *MockClock) FakeNow() (ret0 time.Time) {
+	call := capture("MockClock_FakeNow", []interface{}{})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockClock_FakeNow_Faults.Inject([]interface{}{}, nil) {
+		return
+	}
+	if MockClock_FakeNow == nil {
+		unstubbed(p.Defaults, "MockClock_FakeNow", []interface{}{}, &ret0)
+		return
+	}
+	return MockClock_FakeNow()
+}
+
+// MockClockFakeNowCall is a recorded call of FakeNow, results are zero when the call did not return
+type MockClockFakeNowCall struct {
+	Ret0 time.Time
+}
+
+// FakeNowCalls returns the recorded calls of FakeNow in order
+func (p *MockClock) FakeNowCalls() []MockClockFakeNowCall {
+	calls := make([]MockClockFakeNowCall, 0)
+	for _, c := range Calls("MockClock_FakeNow") {
+		call := MockClockFakeNowCall{}
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(time.Time)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// FakeNowCallCount returns the number of calls of FakeNow
+func (p *MockClock) FakeNowCallCount() int {
+	return NumCalls("MockClock_FakeNow")
+}
+
+var MockClock_FakeNow_Returns = &returns{outs: 1}
+
+// MockClock_FakeNow_Results are the results of one call of FakeNow
+type MockClock_FakeNow_Results struct {
+	R0 time.Time
+}
+
+// FakeNowReturnsOnCall sets the results of the nth call of FakeNow, counting from 0
+func (p *MockClock) FakeNowReturnsOnCall(n int, ret0 time.Time) {
+	if MockClock_FakeNow_Returns.onCall == nil {
+		MockClock_FakeNow_Returns.onCall = make(map[int][]interface{})
+	}
+	MockClock_FakeNow_Returns.onCall[n] = []interface{}{ret0}
+	p.useFakeNowReturns()
+}
+
+// FakeNowReturnsSequence sets the results of successive calls of FakeNow, calls beyond the sequence fail
+func (p *MockClock) FakeNowReturnsSequence(results ...MockClock_FakeNow_Results) {
+	for _, r := range results {
+		MockClock_FakeNow_Returns.sequence = append(MockClock_FakeNow_Returns.sequence, []interface{}{r.R0})
+	}
+	p.useFakeNowReturns()
+}
+
+// FakeNowReturnsWhen sets the results of calls of FakeNow with the given arguments
+func (p *MockClock) FakeNowReturnsWhen(ret0 time.Time) {
+	w := whenReturns{params: []interface{}{}, results: []interface{}{ret0}}
+	MockClock_FakeNow_Returns.when = append(MockClock_FakeNow_Returns.when, w)
+	p.useFakeNowReturns()
+}
+
+func (p *MockClock) useFakeNowReturns() {
+	MockClock_FakeNow_Returns.start("MockClock_FakeNow")
+	MockClock_FakeNow = func() time.Time {
+		res := MockClock_FakeNow_Returns.results(p.Defaults, "MockClock_FakeNow", []interface{}{})
+		if res == nil {
+			var ret0 time.Time
+			unstubbed(p.Defaults, "MockClock_FakeNow", []interface{}{}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(time.Time)
+		return ret0
+	}
+}
+
+// MockClock_FakeNow_Reset clears the stub of FakeNow and what is configured for it
+func MockClock_FakeNow_Reset() {
+	MockClock_FakeNow = nil
+	MockClock_FakeNow_Faults.Reset()
+	MockClock_FakeNow_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockClock_FakeNow_Reset)
+}
+
+type FakeFetch func(a1 context.Context, a2 string) (*Svc3, error)
+
+var MockClock_FakeFetch FakeFetch
+
+// MockClock_FakeFetch_Faults are injected into calls of FakeFetch before the stub is invoked
+var MockClock_FakeFetch_Faults Faults
+
+// MockClock_FakeFetch_Latency is waited for by calls of FakeFetch before the stub is invoked
+var MockClock_FakeFetch_Latency Latency
+
+func (p *MockClock) FakeFetch(a1 context.Context, a2 string) (ret0 *Svc3, ret1 error) {
+	call := capture("MockClock_FakeFetch", []interface{}{a1, a2})
+	defer func() {
+		call.done(recover(), ret0, ret1)
+	}()
+	if MockClock_FakeFetch_Faults.Inject([]interface{}{a1, a2}, &ret1) {
+		return
+	}
+	if err := MockClock_FakeFetch_Latency.Wait(a1); err != nil {
+		ret1 = err
+		return
+	}
+	if MockClock_FakeFetch == nil {
+		unstubbed(p.Defaults, "MockClock_FakeFetch", []interface{}{a1, a2}, &ret0, &ret1)
+		return
+	}
+	return MockClock_FakeFetch(a1, a2)
+}
+
+// MockClockFakeFetchCall is a recorded call of FakeFetch, results are zero when the call did not return
+type MockClockFakeFetchCall struct {
+	A1   context.Context
+	A2   string
+	Ret0 *Svc3
+	Ret1 error
+}
+
+// FakeFetchCalls returns the recorded calls of FakeFetch in order
+func (p *MockClock) FakeFetchCalls() []MockClockFakeFetchCall {
+	calls := make([]MockClockFakeFetchCall, 0)
+	for _, c := range Calls("MockClock_FakeFetch") {
+		call := MockClockFakeFetchCall{}
+		call.A1, _ = c.Params[0].(context.Context)
+		call.A2, _ = c.Params[1].(string)
+		if len(c.Results) == 2 {
+			call.Ret0, _ = c.Results[0].(*Svc3)
+			call.Ret1, _ = c.Results[1].(error)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// FakeFetchCallCount returns the number of calls of FakeFetch
+func (p *MockClock) FakeFetchCallCount() int {
+	return NumCalls("MockClock_FakeFetch")
+}
+
+var MockClock_FakeFetch_Returns = &returns{outs: 2}
+
+// MockClock_FakeFetch_Results are the results of one call of FakeFetch
+type MockClock_FakeFetch_Results struct {
+	R0 *Svc3
+	R1 error
+}
+
+// FakeFetchReturnsOnCall sets the results of the nth call of FakeFetch, counting from 0
+func (p *MockClock) FakeFetchReturnsOnCall(n int, ret0 *Svc3, ret1 error) {
+	if MockClock_FakeFetch_Returns.onCall == nil {
+		MockClock_FakeFetch_Returns.onCall = make(map[int][]interface{})
+	}
+	MockClock_FakeFetch_Returns.onCall[n] = []interface{}{ret0, ret1}
+	p.useFakeFetchReturns()
+}
+
+// FakeFetchReturnsSequence sets the results of successive calls of FakeFetch, calls beyond the sequence fail
+func (p *MockClock) FakeFetchReturnsSequence(results ...MockClock_FakeFetch_Results) {
+	for _, r := range results {
+		MockClock_FakeFetch_Returns.sequence = append(MockClock_FakeFetch_Returns.sequence, []interface{}{r.R0, r.R1})
+	}
+	p.useFakeFetchReturns()
+}
+
+// FakeFetchReturnsWhen sets the results of calls of FakeFetch with the given arguments
+func (p *MockClock) FakeFetchReturnsWhen(a1 context.Context, a2 string, ret0 *Svc3, ret1 error) {
+	w := whenReturns{params: []interface{}{a1, a2}, results: []interface{}{ret0, ret1}}
+	MockClock_FakeFetch_Returns.when = append(MockClock_FakeFetch_Returns.when, w)
+	p.useFakeFetchReturns()
+}
+
+func (p *MockClock) useFakeFetchReturns() {
+	MockClock_FakeFetch_Returns.start("MockClock_FakeFetch")
+	MockClock_FakeFetch = func(a1 context.Context, a2 string) (*Svc3, error) {
+		res := MockClock_FakeFetch_Returns.results(p.Defaults, "MockClock_FakeFetch", []interface{}{a1, a2})
+		if res == nil {
+			var ret0 *Svc3
+			var ret1 error
+			unstubbed(p.Defaults, "MockClock_FakeFetch", []interface{}{a1, a2}, &ret0, &ret1)
+			return ret0, ret1
+		}
+		ret0, _ := res[0].(*Svc3)
+		ret1, _ := res[1].(error)
+		return ret0, ret1
+	}
+}
+
+// MockClock_FakeFetch_Reset clears the stub of FakeFetch and what is configured for it
+func MockClock_FakeFetch_Reset() {
+	MockClock_FakeFetch = nil
+	MockClock_FakeFetch_Faults.Reset()
+	MockClock_FakeFetch_Latency.Reset()
+	MockClock_FakeFetch_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockClock_FakeFetch_Reset)
+}
+
+type FakeLog func(a1 string)
+
+var MockClock_FakeLog FakeLog
+
+// MockClock_FakeLog_Faults are injected into calls of FakeLog before the stub is invoked
+var MockClock_FakeLog_Faults Faults
+
+func (p *MockClock) FakeLog(a1 string) {
+	call := capture("MockClock_FakeLog", []interface{}{a1})
+	defer func() {
+		call.done(recover())
+	}()
+	if MockClock_FakeLog_Faults.Inject([]interface{}{a1}, nil) {
+		return
+	}
+	if MockClock_FakeLog == nil {
+		unstubbed(p.Defaults, "MockClock_FakeLog", []interface{}{a1})
+		return
+	}
+	MockClock_FakeLog(a1)
+}
+
+// MockClockFakeLogCall is a recorded call of FakeLog, results are zero when the call did not return
+type MockClockFakeLogCall struct {
+	A1 string
+}
+
+// FakeLogCalls returns the recorded calls of FakeLog in order
+func (p *MockClock) FakeLogCalls() []MockClockFakeLogCall {
+	calls := make([]MockClockFakeLogCall, 0)
+	for _, c := range Calls("MockClock_FakeLog") {
+		call := MockClockFakeLogCall{}
+		call.A1, _ = c.Params[0].(string)
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// FakeLogCallCount returns the number of calls of FakeLog
+func (p *MockClock) FakeLogCallCount() int {
+	return NumCalls("MockClock_FakeLog")
+}
+
+// MockClock_FakeLog_Reset clears the stub of FakeLog and what is configured for it
+func MockClock_FakeLog_Reset() {
+	MockClock_FakeLog = nil
+	MockClock_FakeLog_Faults.Reset()
+}
+
+func init() {
+	resets = append(resets, MockClock_FakeLog_Reset)
+}
+
+// End of mock for Clock and its methods
+
+// Begin of mock for L1 and its methods
+type MockL1 struct {
+	s     string
+	S1    string
+	time  time.Duration
+	Time2 time.Duration
+	L2    L2
+	Il2   Il2
+	PL2   *L2
+	DEPS_ interface{}
+	// Fixture, when set, replays recorded results for calls without a stub
+	Fixture *Fixture
+	// Defaults overrides UnstubbedDefaults for this mock
+	Defaults *Defaults
+}
+
+// init provides MockL1 as the mock of OrdCtrl to Swap
+func init() {
+	RegisterMockFactory("github.com/rvauradkar1/mockgen", "OrdCtrl", func() interface{} {
+		return &MockL1{}
+	})
+}
+
+type LM1 func(a1 int, a2 float32) (string, *int)
+
+var MockL1_LM1 LM1
+
+// MockL1_LM1_Faults are injected into calls of LM1 before the stub is invoked
+var MockL1_LM1_Faults Faults
+
+func (v MockL1) LM1(a1 int, a2 float32) (ret0 string, ret1 *int) {
+	call := capture("MockL1_LM1", []interface{}{a1, a2})
+	defer func() {
+		call.done(recover(), ret0, ret1)
+	}()
+	if MockL1_LM1_Faults.Inject([]interface{}{a1, a2}, nil) {
+		return
+	}
+	if MockL1_LM1 == nil && v.Fixture != nil {
+		if err := v.Fixture.Replay("LM1", []interface{}{a1, a2}, &ret0, &ret1); err != nil {
+			failed(v.Defaults, "%s", err)
+		}
+		return
+	}
+	if MockL1_LM1 == nil {
+		unstubbed(v.Defaults, "MockL1_LM1", []interface{}{a1, a2}, &ret0, &ret1)
+		return
+	}
+	return MockL1_LM1(a1, a2)
+}
+
+// MockL1LM1Call is a recorded call of LM1, results are zero when the call did not return
+type MockL1LM1Call struct {
+	A1   int
+	A2   float32
+	Ret0 string
+	Ret1 *int
+}
+
+// LM1Calls returns the recorded calls of LM1 in order
+func (v MockL1) LM1Calls() []MockL1LM1Call {
+	calls := make([]MockL1LM1Call, 0)
+	for _, c := range Calls("MockL1_LM1") {
+		call := MockL1LM1Call{}
+		call.A1, _ = c.Params[0].(int)
+		call.A2, _ = c.Params[1].(float32)
+		if len(c.Results) == 2 {
+			call.Ret0, _ = c.Results[0].(string)
+			call.Ret1, _ = c.Results[1].(*int)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// LM1CallCount returns the number of calls of LM1
+func (v MockL1) LM1CallCount() int {
+	return NumCalls("MockL1_LM1")
+}
+
+var MockL1_LM1_Returns = &returns{outs: 2}
+
+// MockL1_LM1_Results are the results of one call of LM1
+type MockL1_LM1_Results struct {
+	R0 string
+	R1 *int
+}
+
+// LM1ReturnsOnCall sets the results of the nth call of LM1, counting from 0
+func (v MockL1) LM1ReturnsOnCall(n int, ret0 string, ret1 *int) {
+	if MockL1_LM1_Returns.onCall == nil {
+		MockL1_LM1_Returns.onCall = make(map[int][]interface{})
+	}
+	MockL1_LM1_Returns.onCall[n] = []interface{}{ret0, ret1}
+	v.useLM1Returns()
+}
+
+// LM1ReturnsSequence sets the results of successive calls of LM1, calls beyond the sequence fail
+func (v MockL1) LM1ReturnsSequence(results ...MockL1_LM1_Results) {
+	for _, r := range results {
+		MockL1_LM1_Returns.sequence = append(MockL1_LM1_Returns.sequence, []interface{}{r.R0, r.R1})
+	}
+	v.useLM1Returns()
+}
+
+// LM1ReturnsWhen sets the results of calls of LM1 with the given arguments
+func (v MockL1) LM1ReturnsWhen(a1 int, a2 float32, ret0 string, ret1 *int) {
+	w := whenReturns{params: []interface{}{a1, a2}, results: []interface{}{ret0, ret1}}
+	MockL1_LM1_Returns.when = append(MockL1_LM1_Returns.when, w)
+	v.useLM1Returns()
+}
+
+func (v MockL1) useLM1Returns() {
+	MockL1_LM1_Returns.start("MockL1_LM1")
+	MockL1_LM1 = func(a1 int, a2 float32) (string, *int) {
+		res := MockL1_LM1_Returns.results(v.Defaults, "MockL1_LM1", []interface{}{a1, a2})
+		if res == nil {
+			var ret0 string
+			var ret1 *int
+			unstubbed(v.Defaults, "MockL1_LM1", []interface{}{a1, a2}, &ret0, &ret1)
+			return ret0, ret1
+		}
+		ret0, _ := res[0].(string)
+		ret1, _ := res[1].(*int)
+		return ret0, ret1
+	}
+}
+
+// MockL1_LM1_Reset clears the stub of LM1 and what is configured for it
+func MockL1_LM1_Reset() {
+	MockL1_LM1 = nil
+	MockL1_LM1_Faults.Reset()
+	MockL1_LM1_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockL1_LM1_Reset)
+}
+
+type LM2 func(a1 time.Duration, a2 float32) (string, time.Duration)
+
+var MockL1_LM2 LM2
+
+// MockL1_LM2_Faults are injected into calls of LM2 before the stub is invoked
+var MockL1_LM2_Faults Faults
+
+func (p *MockL1) LM2(a1 time.Duration, a2 float32) (ret0 string, ret1 time.Duration) {
+	call := capture("MockL1_LM2", []interface{}{a1, a2})
+	defer func() {
+		call.done(recover(), ret0, ret1)
+	}()
+	if MockL1_LM2_Faults.Inject([]interface{}{a1, a2}, nil) {
+		return
+	}
+	if MockL1_LM2 == nil && p.Fixture != nil {
+		if err := p.Fixture.Replay("LM2", []interface{}{a1, a2}, &ret0, &ret1); err != nil {
+			failed(p.Defaults, "%s", err)
+		}
+		return
+	}
+	if MockL1_LM2 == nil {
+		unstubbed(p.Defaults, "MockL1_LM2", []interface{}{a1, a2}, &ret0, &ret1)
+		return
+	}
+	return MockL1_LM2(a1, a2)
+}
+
+// MockL1LM2Call is a recorded call of LM2, results are zero when the call did not return
+type MockL1LM2Call struct {
+	A1   time.Duration
+	A2   float32
+	Ret0 string
+	Ret1 time.Duration
+}
+
+// LM2Calls returns the recorded calls of LM2 in order
+func (p *MockL1) LM2Calls() []MockL1LM2Call {
+	calls := make([]MockL1LM2Call, 0)
+	for _, c := range Calls("MockL1_LM2") {
+		call := MockL1LM2Call{}
+		call.A1, _ = c.Params[0].(time.Duration)
+		call.A2, _ = c.Params[1].(float32)
+		if len(c.Results) == 2 {
+			call.Ret0, _ = c.Results[0].(string)
+			call.Ret1, _ = c.Results[1].(time.Duration)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// LM2CallCount returns the number of calls of LM2
+func (p *MockL1) LM2CallCount() int {
+	return NumCalls("MockL1_LM2")
+}
+
+var MockL1_LM2_Returns = &returns{outs: 2}
+
+// MockL1_LM2_Results are the results of one call of LM2
+type MockL1_LM2_Results struct {
+	R0 string
+	R1 time.Duration
+}
+
+// LM2ReturnsOnCall sets the results of the nth call of LM2, counting from 0
+func (p *MockL1) LM2ReturnsOnCall(n int, ret0 string, ret1 time.Duration) {
+	if MockL1_LM2_Returns.onCall == nil {
+		MockL1_LM2_Returns.onCall = make(map[int][]interface{})
+	}
+	MockL1_LM2_Returns.onCall[n] = []interface{}{ret0, ret1}
+	p.useLM2Returns()
+}
+
+// LM2ReturnsSequence sets the results of successive calls of LM2, calls beyond the sequence fail
+func (p *MockL1) LM2ReturnsSequence(results ...MockL1_LM2_Results) {
+	for _, r := range results {
+		MockL1_LM2_Returns.sequence = append(MockL1_LM2_Returns.sequence, []interface{}{r.R0, r.R1})
+	}
+	p.useLM2Returns()
+}
+
+// LM2ReturnsWhen sets the results of calls of LM2 with the given arguments
+func (p *MockL1) LM2ReturnsWhen(a1 time.Duration, a2 float32, ret0 string, ret1 time.Duration) {
+	w := whenReturns{params: []interface{}{a1, a2}, results: []interface{}{ret0, ret1}}
+	MockL1_LM2_Returns.when = append(MockL1_LM2_Returns.when, w)
+	p.useLM2Returns()
+}
+
+func (p *MockL1) useLM2Returns() {
+	MockL1_LM2_Returns.start("MockL1_LM2")
+	MockL1_LM2 = func(a1 time.Duration, a2 float32) (string, time.Duration) {
+		res := MockL1_LM2_Returns.results(p.Defaults, "MockL1_LM2", []interface{}{a1, a2})
+		if res == nil {
+			var ret0 string
+			var ret1 time.Duration
+			unstubbed(p.Defaults, "MockL1_LM2", []interface{}{a1, a2}, &ret0, &ret1)
+			return ret0, ret1
+		}
+		ret0, _ := res[0].(string)
+		ret1, _ := res[1].(time.Duration)
+		return ret0, ret1
+	}
+}
+
+// MockL1_LM2_Reset clears the stub of LM2 and what is configured for it
+func MockL1_LM2_Reset() {
+	MockL1_LM2 = nil
+	MockL1_LM2_Faults.Reset()
+	MockL1_LM2_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockL1_LM2_Reset)
+}
+
+type LM3 func(a1 *float32) (string, time.Duration)
+
+var MockL1_LM3 LM3
+
+// MockL1_LM3_Faults are injected into calls of LM3 before the stub is invoked
+var MockL1_LM3_Faults Faults
+
+// MockL1_LM3_ArgWrites are written into the arguments of calls of LM3 before the stub is invoked
+var MockL1_LM3_ArgWrites ArgWrites
+
+// LM3SetsArg writes value into the nth argument of calls of LM3, counting from 0
+func (p *MockL1) LM3SetsArg(n int, value interface{}) {
+	MockL1_LM3_ArgWrites.Set(n, value)
+}
+func (p *MockL1) LM3(a1 *float32) (ret0 string, ret1 time.Duration) {
+	call := capture("MockL1_LM3", []interface{}{a1})
+	defer func() {
+		call.done(recover(), ret0, ret1)
+	}()
+	if MockL1_LM3_Faults.Inject([]interface{}{a1}, nil) {
+		return
+	}
+	if err := MockL1_LM3_ArgWrites.Apply([]interface{}{a1}); err != nil {
+		failed(p.Defaults, "MockL1_LM3 %s", err)
+	}
+	if MockL1_LM3 == nil && p.Fixture != nil {
+		if err := p.Fixture.Replay("LM3", []interface{}{a1}, &ret0, &ret1); err != nil {
+			failed(p.Defaults, "%s", err)
+		}
+		return
+	}
+	if MockL1_LM3 == nil {
+		unstubbed(p.Defaults, "MockL1_LM3", []interface{}{a1}, &ret0, &ret1)
+		return
+	}
+	return MockL1_LM3(a1)
+}
+
+// MockL1LM3Call is a recorded call of LM3, results are zero when the call did not return
+type MockL1LM3Call struct {
+	A1   *float32
+	Ret0 string
+	Ret1 time.Duration
+}
+
+// LM3Calls returns the recorded calls of LM3 in order
+func (p *MockL1) LM3Calls() []MockL1LM3Call {
+	calls := make([]MockL1LM3Call, 0)
+	for _, c := range Calls("MockL1_LM3") {
+		call := MockL1LM3Call{}
+		call.A1, _ = c.Params[0].(*float32)
+		if len(c.Results) == 2 {
+			call.Ret0, _ = c.Results[0].(string)
+			call.Ret1, _ = c.Results[1].(time.Duration)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// LM3CallCount returns the number of calls of LM3
+func (p *MockL1) LM3CallCount() int {
+	return NumCalls("MockL1_LM3")
+}
+
+var MockL1_LM3_Returns = &returns{outs: 2}
+
+// MockL1_LM3_Results are the results of one call of LM3
+type MockL1_LM3_Results struct {
+	R0 string
+	R1 time.Duration
+}
+
+// LM3ReturnsOnCall sets the results of the nth call of LM3, counting from 0
+func (p *MockL1) LM3ReturnsOnCall(n int, ret0 string, ret1 time.Duration) {
+	if MockL1_LM3_Returns.onCall == nil {
+		MockL1_LM3_Returns.onCall = make(map[int][]interface{})
+	}
+	MockL1_LM3_Returns.onCall[n] = []interface{}{ret0, ret1}
+	p.useLM3Returns()
+}
+
+// LM3ReturnsSequence sets the results of successive calls of LM3, calls beyond the sequence fail
+func (p *MockL1) LM3ReturnsSequence(results ...MockL1_LM3_Results) {
+	for _, r := range results {
+		MockL1_LM3_Returns.sequence = append(MockL1_LM3_Returns.sequence, []interface{}{r.R0, r.R1})
+	}
+	p.useLM3Returns()
+}
+
+// LM3ReturnsWhen sets the results of calls of LM3 with the given arguments
+func (p *MockL1) LM3ReturnsWhen(a1 *float32, ret0 string, ret1 time.Duration) {
+	w := whenReturns{params: []interface{}{a1}, results: []interface{}{ret0, ret1}}
+	MockL1_LM3_Returns.when = append(MockL1_LM3_Returns.when, w)
+	p.useLM3Returns()
+}
+
+func (p *MockL1) useLM3Returns() {
+	MockL1_LM3_Returns.start("MockL1_LM3")
+	MockL1_LM3 = func(a1 *float32) (string, time.Duration) {
+		res := MockL1_LM3_Returns.results(p.Defaults, "MockL1_LM3", []interface{}{a1})
+		if res == nil {
+			var ret0 string
+			var ret1 time.Duration
+			unstubbed(p.Defaults, "MockL1_LM3", []interface{}{a1}, &ret0, &ret1)
+			return ret0, ret1
+		}
+		ret0, _ := res[0].(string)
+		ret1, _ := res[1].(time.Duration)
+		return ret0, ret1
+	}
+}
+
+// MockL1_LM3_Reset clears the stub of LM3 and what is configured for it
+func MockL1_LM3_Reset() {
+	MockL1_LM3 = nil
+	MockL1_LM3_Faults.Reset()
+	MockL1_LM3_ArgWrites.Reset()
+	MockL1_LM3_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockL1_LM3_Reset)
+}
+
+// MockL1Recording wraps the real component, recording every call into Fixture
+type MockL1Recording struct {
+	Real    *L1
+	Fixture *Fixture
+}
+
+func (rec *MockL1Recording) LM1(a1 int, a2 float32) (ret0 string, ret1 *int) {
+	ret0, ret1 = rec.Real.LM1(a1, a2)
+	rec.Fixture.Record("LM1", []interface{}{a1, a2}, ret0, ret1)
+	return
+}
+
+func (rec *MockL1Recording) LM2(a1 time.Duration, a2 float32) (ret0 string, ret1 time.Duration) {
+	ret0, ret1 = rec.Real.LM2(a1, a2)
+	rec.Fixture.Record("LM2", []interface{}{a1, a2}, ret0, ret1)
+	return
+}
+
+func (rec *MockL1Recording) LM3(a1 *float32) (ret0 string, ret1 time.Duration) {
+	ret0, ret1 = rec.Real.LM3(a1)
+	rec.Fixture.Record("LM3", []interface{}{a1}, ret0, ret1)
+	return
+}
+
+// End of mock for L1 and its methods
+
+// Begin of mock for L2 and its methods
+type MockL2 struct {
+	s    string
+	time time.Duration
+	Il3  Il3
+	// Spied is the real component, called when no stub is set
+	Spied *L2
+	// Defaults overrides UnstubbedDefaults for this mock
+	Defaults *Defaults
+}
+
+// NewMockL2Spy returns a mock delegating to real unless a stub is set
+func NewMockL2Spy(real *L2) *MockL2 {
+	return &MockL2{Spied: real}
+}
+
+// init provides MockL2 as the mock of CartSvc to Swap
+func init() {
+	RegisterMockFactory("github.com/rvauradkar1/mockgen", "CartSvc", func() interface{} {
+		return &MockL2{}
+	})
+}
+
+type LM21 func(a1 int, a2 float32) string
+
+var MockL2_LM21 LM21
+
+// MockL2_LM21_Faults are injected into calls of LM21 before the stub is invoked
+var MockL2_LM21_Faults Faults
+
+func (v MockL2) LM21(a1 int, a2 float32) (ret0 string) {
+	call := capture("MockL2_LM21", []interface{}{a1, a2})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockL2_LM21_Faults.Inject([]interface{}{a1, a2}, nil) {
+		return
+	}
+	if MockL2_LM21 == nil && v.Spied != nil {
+		return v.Spied.LM21(a1, a2)
+	}
+	if MockL2_LM21 == nil {
+		unstubbed(v.Defaults, "MockL2_LM21", []interface{}{a1, a2}, &ret0)
+		return
+	}
+	return MockL2_LM21(a1, a2)
+}
+
+// MockL2LM21Call is a recorded call of LM21, results are zero when the call did not return
+type MockL2LM21Call struct {
+	A1   int
+	A2   float32
+	Ret0 string
+}
+
+// LM21Calls returns the recorded calls of LM21 in order
+func (v MockL2) LM21Calls() []MockL2LM21Call {
+	calls := make([]MockL2LM21Call, 0)
+	for _, c := range Calls("MockL2_LM21") {
+		call := MockL2LM21Call{}
+		call.A1, _ = c.Params[0].(int)
+		call.A2, _ = c.Params[1].(float32)
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(string)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// LM21CallCount returns the number of calls of LM21
+func (v MockL2) LM21CallCount() int {
+	return NumCalls("MockL2_LM21")
+}
+
+var MockL2_LM21_Returns = &returns{outs: 1}
+
+// MockL2_LM21_Results are the results of one call of LM21
+type MockL2_LM21_Results struct {
+	R0 string
+}
+
+// LM21ReturnsOnCall sets the results of the nth call of LM21, counting from 0
+func (v MockL2) LM21ReturnsOnCall(n int, ret0 string) {
+	if MockL2_LM21_Returns.onCall == nil {
+		MockL2_LM21_Returns.onCall = make(map[int][]interface{})
+	}
+	MockL2_LM21_Returns.onCall[n] = []interface{}{ret0}
+	v.useLM21Returns()
+}
+
+// LM21ReturnsSequence sets the results of successive calls of LM21, calls beyond the sequence fail
+func (v MockL2) LM21ReturnsSequence(results ...MockL2_LM21_Results) {
+	for _, r := range results {
+		MockL2_LM21_Returns.sequence = append(MockL2_LM21_Returns.sequence, []interface{}{r.R0})
+	}
+	v.useLM21Returns()
+}
+
+// LM21ReturnsWhen sets the results of calls of LM21 with the given arguments
+func (v MockL2) LM21ReturnsWhen(a1 int, a2 float32, ret0 string) {
+	w := whenReturns{params: []interface{}{a1, a2}, results: []interface{}{ret0}}
+	MockL2_LM21_Returns.when = append(MockL2_LM21_Returns.when, w)
+	v.useLM21Returns()
+}
+
+func (v MockL2) useLM21Returns() {
+	MockL2_LM21_Returns.start("MockL2_LM21")
+	MockL2_LM21 = func(a1 int, a2 float32) string {
+		res := MockL2_LM21_Returns.results(v.Defaults, "MockL2_LM21", []interface{}{a1, a2})
+		if res == nil {
+			var ret0 string
+			unstubbed(v.Defaults, "MockL2_LM21", []interface{}{a1, a2}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(string)
+		return ret0
+	}
+}
+
+// MockL2_LM21_Reset clears the stub of LM21 and what is configured for it
+func MockL2_LM21_Reset() {
+	MockL2_LM21 = nil
+	MockL2_LM21_Faults.Reset()
+	MockL2_LM21_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockL2_LM21_Reset)
+}
+
+// End of mock for L2 and its methods
+
+// Begin of mock for L3 and its methods
+type MockL3 struct {
+	s    string
+	time time.Duration
+	// Defaults overrides UnstubbedDefaults for this mock
+	Defaults *Defaults
+}
+
+// init provides MockL3 as the mock of AuthSvc to Swap
+func init() {
+	RegisterMockFactory("github.com/rvauradkar1/mockgen", "AuthSvc", func() interface{} {
+		return &MockL3{}
+	})
+}
+
+type MockL3_LM3_Func func(a1 int, a2 float32) string
+
+var MockL3_LM3 MockL3_LM3_Func
+
+// MockL3_LM3_Faults are injected into calls of LM3 before the stub is invoked
+var MockL3_LM3_Faults Faults
+
+func (v MockL3) LM3(a1 int, a2 float32) (ret0 string) {
+	call := capture("MockL3_LM3", []interface{}{a1, a2})
+	defer func() {
+		call.done(recover(), ret0)
+	}()
+	if MockL3_LM3_Faults.Inject([]interface{}{a1, a2}, nil) {
+		return
+	}
+	if MockL3_LM3 == nil {
+		unstubbed(v.Defaults, "MockL3_LM3", []interface{}{a1, a2}, &ret0)
+		return
+	}
+	return MockL3_LM3(a1, a2)
+}
+
+// MockL3LM3Call is a recorded call of LM3, results are zero when the call did not return
+type MockL3LM3Call struct {
+	A1   int
+	A2   float32
+	Ret0 string
+}
+
+// LM3Calls returns the recorded calls of LM3 in order
+func (v MockL3) LM3Calls() []MockL3LM3Call {
+	calls := make([]MockL3LM3Call, 0)
+	for _, c := range Calls("MockL3_LM3") {
+		call := MockL3LM3Call{}
+		call.A1, _ = c.Params[0].(int)
+		call.A2, _ = c.Params[1].(float32)
+		if len(c.Results) == 1 {
+			call.Ret0, _ = c.Results[0].(string)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// LM3CallCount returns the number of calls of LM3
+func (v MockL3) LM3CallCount() int {
+	return NumCalls("MockL3_LM3")
+}
+
+var MockL3_LM3_Returns = &returns{outs: 1}
+
+// MockL3_LM3_Results are the results of one call of LM3
+type MockL3_LM3_Results struct {
+	R0 string
+}
+
+// LM3ReturnsOnCall sets the results of the nth call of LM3, counting from 0
+func (v MockL3) LM3ReturnsOnCall(n int, ret0 string) {
+	if MockL3_LM3_Returns.onCall == nil {
+		MockL3_LM3_Returns.onCall = make(map[int][]interface{})
+	}
+	MockL3_LM3_Returns.onCall[n] = []interface{}{ret0}
+	v.useLM3Returns()
+}
+
+// LM3ReturnsSequence sets the results of successive calls of LM3, calls beyond the sequence fail
+func (v MockL3) LM3ReturnsSequence(results ...MockL3_LM3_Results) {
+	for _, r := range results {
+		MockL3_LM3_Returns.sequence = append(MockL3_LM3_Returns.sequence, []interface{}{r.R0})
+	}
+	v.useLM3Returns()
+}
+
+// LM3ReturnsWhen sets the results of calls of LM3 with the given arguments
+func (v MockL3) LM3ReturnsWhen(a1 int, a2 float32, ret0 string) {
+	w := whenReturns{params: []interface{}{a1, a2}, results: []interface{}{ret0}}
+	MockL3_LM3_Returns.when = append(MockL3_LM3_Returns.when, w)
+	v.useLM3Returns()
+}
+
+func (v MockL3) useLM3Returns() {
+	MockL3_LM3_Returns.start("MockL3_LM3")
+	MockL3_LM3 = func(a1 int, a2 float32) string {
+		res := MockL3_LM3_Returns.results(v.Defaults, "MockL3_LM3", []interface{}{a1, a2})
+		if res == nil {
+			var ret0 string
+			unstubbed(v.Defaults, "MockL3_LM3", []interface{}{a1, a2}, &ret0)
+			return ret0
+		}
+		ret0, _ := res[0].(string)
+		return ret0
+	}
+}
+
+// MockL3_LM3_Reset clears the stub of LM3 and what is configured for it
+func MockL3_LM3_Reset() {
+	MockL3_LM3 = nil
+	MockL3_LM3_Faults.Reset()
+	MockL3_LM3_Returns.reset()
+}
+
+func init() {
+	resets = append(resets, MockL3_LM3_Reset)
+}
+
+// End of mock for L3 and its methods
@@ -0,0 +1,112 @@
+package mock
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// module describes the Go module whose packages mocks are generated into
+type module struct {
+	// Path is the module path declared in go.mod
+	Path string
+	// Dir is the directory containing go.mod
+	Dir string
+}
+
+// findModule walks up from dir to the nearest go.mod and reads its module path
+func findModule(dir string) (*module, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		gomod := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(gomod); err == nil {
+			path, err := modulePath(gomod)
+			if err != nil {
+				return nil, err
+			}
+			return &module{Path: path, Dir: dir}, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("no go.mod found in %s or any parent directory", dir)
+		}
+		dir = parent
+	}
+}
+
+// modulePath reads the module directive of a go.mod file
+func modulePath(gomod string) (string, error) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`"), nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s has no module directive", gomod)
+}
+
+// pkgDir maps an import path to its source directory, inside the module or its vendor directory
+func (m *module) pkgDir(pkgPath string) (string, error) {
+	if pkgPath == "" {
+		return "", fmt.Errorf("type has no package path, only named types of a package can be mocked")
+	}
+	dir := ""
+	if pkgPath == m.Path {
+		dir = m.Dir
+	} else if strings.HasPrefix(pkgPath, m.Path+"/") {
+		dir = filepath.Join(m.Dir, filepath.FromSlash(strings.TrimPrefix(pkgPath, m.Path+"/")))
+	} else {
+		dir = filepath.Join(m.Dir, "vendor", filepath.FromSlash(pkgPath))
+	}
+	fi, err := os.Stat(dir)
+	if err != nil || !fi.IsDir() {
+		return "", fmt.Errorf("package %s cannot be mapped to a directory of module %s in %s", pkgPath, m.Path, m.Dir)
+	}
+	return dir, nil
+}
+
+// WithModule sets the directory of the module containing the components, defaults to the nearest go.mod
+// above the working directory
+func WithModule(dir string) Option {
+	return func(b *builder) {
+		b.ModuleDir = dir
+	}
+}
+
+// dir returns the source directory of a package, locating the module on first use
+func (b *builder) dir(pkgPath string) (string, error) {
+	if b.module == nil {
+		start := b.ModuleDir
+		if start == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return "", err
+			}
+			start = wd
+		}
+		m, err := findModule(start)
+		if err != nil {
+			return "", err
+		}
+		b.module = m
+	}
+	return b.module.pkgDir(pkgPath)
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func writeModule(t *testing.T, path string, dirs ...string) string {
+	root := t.TempDir()
+	gomod := "// test module\nmodule " + path + " // trailing\n\ngo 1.18\n"
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(d)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func Test_findModule(t *testing.T) {
+	root := writeModule(t, "example.com/mock", "mock/lvl1")
+	m, err := findModule(filepath.Join(root, "mock", "lvl1"))
+	if err != nil {
+		t.Fatalf("no error expected, but got %s", err)
+	}
+	if m.Path != "example.com/mock" {
+		t.Errorf("module path should have been %s, but was %s", "example.com/mock", m.Path)
+	}
+	if m.Dir != root {
+		t.Errorf("module dir should have been %s, but was %s", root, m.Dir)
+	}
+}
+
+func Test_pkgDir(t *testing.T) {
+	root := writeModule(t, "example.com/mock", "mock/lvl1", "vendor/other.org/lib")
+	m := &module{Path: "example.com/mock", Dir: root}
+	dir, err := m.pkgDir("example.com/mock")
+	if err != nil || dir != root {
+		t.Errorf("dir should have been %s, but was %s (%v)", root, dir, err)
+	}
+	// module path appearing twice in the import path
+	dir, err = m.pkgDir("example.com/mock/mock/lvl1")
+	if want := filepath.Join(root, "mock", "lvl1"); err != nil || dir != want {
+		t.Errorf("dir should have been %s, but was %s (%v)", want, dir, err)
+	}
+	dir, err = m.pkgDir("other.org/lib")
+	if want := filepath.Join(root, "vendor", "other.org", "lib"); err != nil || dir != want {
+		t.Errorf("dir should have been %s, but was %s (%v)", want, dir, err)
+	}
+	if _, err = m.pkgDir("example.com/mockery"); err == nil {
+		t.Errorf("package sharing only a prefix with the module should have errored out")
+	}
+	if _, err = m.pkgDir(""); err == nil {
+		t.Errorf("blank package path should have errored out")
+	}
+}
+
+func Test_registerDir(t *testing.T) {
+	wd, _ := os.Getwd()
+	b := New("mock").(*builder)
+	b.register3(fuse.Entry{Name: "l1", Instance: &L1{}})
+	if len(b.Errors) != 0 {
+		t.Fatalf("no errors expected, but got %v", b.Errors)
+	}
+	if b.Registry["l1"].Basepath != wd {
+		t.Errorf("basepath should have been %s, but was %s", wd, b.Registry["l1"].Basepath)
+	}
+	b = New("mock", WithModule(writeModule(t, "example.com/other"))).(*builder)
+	b.register3(fuse.Entry{Name: "l1", Instance: &L1{}})
+	if len(b.Errors) != 1 {
+		t.Errorf("length of errors should have been %d, but was %d", 1, len(b.Errors))
+	}
+}
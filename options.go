@@ -0,0 +1,98 @@
+package mock
+
+import (
+	"io/ioutil"
+	"log"
+)
+
+// Option configures the builder returned by New
+type Option func(*builder)
+
+// Sink receives the source of a generated mock file along with the path it is meant for
+type Sink func(path string, src []byte) error
+
+// WriteFile is the default Sink, it writes mocks to disk
+func WriteFile(path string, src []byte) error {
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+// Recorder selects how generated mocks record calls
+type Recorder int
+
+const (
+	// RecordCalls emits the call recorder (NumCalls, CallParams) and captures every call, the default
+	RecordCalls Recorder = iota
+	// RecordNone emits mocks that only delegate to their stubs
+	RecordNone
+)
+
+// WithLogger prints progress messages of level Info and above to a logger
+func WithLogger(l *log.Logger) Option {
+	return func(b *builder) {
+		b.Reporter = LogReporter(l, Info)
+	}
+}
+
+// WithOutput sets the sink generated mocks are written to, defaults to WriteFile
+func WithOutput(s Sink) Option {
+	return func(b *builder) {
+		b.Output = s
+	}
+}
+
+// WithFileName sets the name of generated files, a %s in name is replaced by the lower case component name.
+// Defaults to mocks_test.go
+func WithFileName(name string) Option {
+	return func(b *builder) {
+		b.FileName = name
+	}
+}
+
+// WithNaming sets the prefix and suffix of generated mock type names, defaults to prefix Mock
+func WithNaming(prefix, suffix string) Option {
+	return func(b *builder) {
+		b.Prefix = prefix
+		b.Suffix = suffix
+	}
+}
+
+// WithRecorder sets the recorder style of generated mocks
+func WithRecorder(r Recorder) Option {
+	return func(b *builder) {
+		b.Recorder = r
+	}
+}
+
+// WithFormat turns gofmt formatting of generated mocks on or off, on by default
+func WithFormat(format bool) Option {
+	return func(b *builder) {
+		b.Format = format
+	}
+}
+
+// WithSpy generates classic mocks of the given components, or of all components when none are given, as spies.
+// A spy wraps the real component and delegates every call to it unless the method's stub is set
+func WithSpy(components ...string) Option {
+	return func(b *builder) {
+		if len(components) == 0 {
+			b.Spies[""] = true
+		}
+		for _, c := range components {
+			b.Spies[c] = true
+		}
+	}
+}
+
+// WithFixture generates classic mocks of the given components, or of all components when none are given, that
+// replay a Fixture. A recording wrapper (MockXRecording) is generated alongside to record the fixture from the real
+// component
+func WithFixture(components ...string) Option {
+	return func(b *builder) {
+		if len(components) == 0 {
+			b.Fixtures[""] = true
+		}
+		for _, c := range components {
+			b.Fixtures[c] = true
+		}
+	}
+}
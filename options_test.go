@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func generate(t *testing.T, opts ...Option) map[string]string {
+	files := make(map[string]string)
+	sink := func(path string, src []byte) error {
+		files[filepath.Base(path)] = string(src)
+		return nil
+	}
+	m := New("mock", append([]Option{WithOutput(sink)}, opts...)...)
+	entries := make([]fuse.Entry, 0)
+	entries = append(entries, fuse.Entry{Name: "L1", Instance: &L1{}})
+	entries = append(entries, fuse.Entry{Name: "L2", Instance: &L2{}})
+	if errs := m.Register(entries); len(errs) != 0 {
+		t.Errorf("no errors expected, but got %v", errs)
+	}
+	if errs := m.Generate(); len(errs) != 0 {
+		t.Errorf("no errors expected, but got %v", errs)
+	}
+	return files
+}
+
+func Test_options(t *testing.T) {
+	var logs bytes.Buffer
+	files := generate(t, WithLogger(log.New(&logs, "", 0)), WithFileName("mock_%s_test.go"), WithNaming("Fake", "Stub"))
+	if len(files) != 2 {
+		t.Fatalf("number of files should have been %d, but was %d", 2, len(files))
+	}
+	s, ok := files["mock_l1_test.go"]
+	if !ok {
+		t.Fatalf("file mock_l1_test.go should have been generated, but got %v", files)
+	}
+	if !strings.Contains(s, "type FakeL1Stub struct {") {
+		t.Errorf("should have contained '%s'", "type FakeL1Stub struct {")
+	}
+	if !strings.Contains(s, `capture("FakeL1Stub_LM1", []interface{}{a1, a2})`) {
+		t.Errorf("should have contained formatted '%s'", `capture("FakeL1Stub_LM1", []interface{}{a1, a2})`)
+	}
+	if !strings.Contains(logs.String(), "info component_registered component=L1") {
+		t.Errorf("logger should have received progress messages")
+	}
+}
+
+func Test_recordNone(t *testing.T) {
+	files := generate(t, WithRecorder(RecordNone), WithFormat(false))
+	s := files["mocks_test.go"]
+	for _, unwanted := range []string{"capture(", "ReturnsOnCall", "LM1Calls"} {
+		if strings.Contains(s, unwanted) {
+			t.Errorf("should NOT have contained '%s'", unwanted)
+		}
+	}
+	if !strings.Contains(s, "return MockL") {
+		t.Errorf("should have contained '%s'", "return MockL")
+	}
+}
+
+func Test_concurrentGenerate(t *testing.T) {
+	prefixes := []string{"A", "B", "C", "D"}
+	results := make([]map[string]string, len(prefixes))
+	var wg sync.WaitGroup
+	for i := range prefixes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var logs bytes.Buffer
+			results[i] = generate(t, WithNaming(prefixes[i], ""), WithLogger(log.New(&logs, "", 0)))
+		}(i)
+	}
+	wg.Wait()
+	for i, files := range results {
+		s := files["mocks_test.go"]
+		for j, p := range prefixes {
+			has := strings.Contains(s, "type "+p+"L2 struct")
+			if i == j && !has {
+				t.Errorf("builder %d should have generated %sL2", i, p)
+			}
+			if i != j && has {
+				t.Errorf("builder %d should NOT have generated %sL2", i, p)
+			}
+		}
+	}
+}
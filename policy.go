@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy decides whether Register may be called from the given caller file
+type Policy func(file string) error
+
+// toolingBuild is set when built with the `mockgen` tag, see policy_tooling.go
+var toolingBuild = false
+
+// TestOnly is the default policy, it allows Register only from test code unless built with the `mockgen` tag
+func TestOnly(file string) error {
+	if toolingBuild || strings.HasSuffix(file, "_test.go") {
+		return nil
+	}
+	return fmt.Errorf("registration can only be done from within test code, not production code, caller was %s", file)
+}
+
+// AllowAll allows Register from anywhere, meant for generator commands and tooling wrappers
+func AllowAll(file string) error {
+	return nil
+}
+
+// WithPolicy sets the policy guarding Register
+func WithPolicy(p Policy) Option {
+	return func(b *builder) {
+		b.Policy = p
+	}
+}
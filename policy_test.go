@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func Test_testOnly(t *testing.T) {
+	if err := TestOnly("/src/comp/comp_test.go"); err != nil {
+		t.Errorf("test files should have been allowed, but got %s", err)
+	}
+	if err := TestOnly("/src/comp/main.go"); err == nil && !toolingBuild {
+		t.Errorf("production files should NOT have been allowed")
+	}
+	if err := AllowAll("/src/comp/main.go"); err != nil {
+		t.Errorf("all files should have been allowed, but got %s", err)
+	}
+}
+
+func Test_withPolicy(t *testing.T) {
+	file := ""
+	deny := func(f string) error {
+		file = f
+		return errors.New("denied")
+	}
+	m := New("mock", WithPolicy(deny))
+	errs := m.Register([]fuse.Entry{{Name: "l1", Instance: &L1{}}})
+	if len(errs) != 1 {
+		t.Fatalf("length of errors should have been %d, but was %d", 1, len(errs))
+	}
+	if file == "" {
+		t.Errorf("policy should have received the caller file")
+	}
+	if len(m.(*builder).Registry) != 0 {
+		t.Errorf("no components should have been registered when denied")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build mockgen
+// +build mockgen
+
+package mock
+
+// Building with `-tags mockgen` lets generator commands call Register outside of test code
+func init() {
+	toolingBuild = true
+}
@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_records(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := &MockL1{}
+	m.LM2(time.Second, 1.5)
+	MockL1_LM2 = func(t1 time.Duration, f2 float32) (string, time.Duration) { panic("boom") }
+	func() {
+		defer func() { recover() }()
+		m.LM2(0, 0)
+	}()
+	calls := Calls("MockL1_LM2")
+	if NumCalls("MockL1_LM2") != 2 || len(calls) != 2 || len(AllCalls()) != 2 {
+		t.Fatalf("number of calls should have been %d, but was %d", 2, len(calls))
+	}
+	if !reflect.DeepEqual(CallParams("MockL1_LM2")[0], Params{time.Second, float32(1.5)}) {
+		t.Errorf("params should have been [1s 1.5], but were %v", CallParams("MockL1_LM2")[0])
+	}
+	if !calls[0].Ok || !reflect.DeepEqual(calls[0].Results, []interface{}{"", time.Duration(0)}) ||
+		!strings.Contains(calls[0].Caller, "recorder_test.go:") {
+		t.Errorf("first call should have returned zero values from recorder_test.go, but was %+v", calls[0])
+	}
+	if calls[1].Ok || calls[1].Panic != "boom" {
+		t.Errorf("second call should have panicked with boom, but was %+v", calls[1])
+	}
+}
+
+func Test_typedCalls(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := &MockL1{}
+	v := float32(1)
+	m.LM3(&v)
+	calls := m.LM3Calls()
+	if len(calls) != 1 || calls[0].A1 != &v || calls[0].Ret0 != "" || m.LM3CallCount() != 1 || m.LM1CallCount() != 0 {
+		t.Errorf("one typed call of LM3 should have been recorded, but were %+v", calls)
+	}
+}
@@ -0,0 +1,130 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a progress event
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var levels = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levels[l]
+}
+
+// MarshalText renders the level by name in JSON events
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText reads a level by name
+func (l *Level) UnmarshalText(text []byte) error {
+	for i, name := range levels {
+		if name == string(text) {
+			*l = Level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown level %s", text)
+}
+
+// Kinds of progress events
+const (
+	EventRegistered = "component_registered"
+	EventResolved   = "dependency_resolved"
+	EventGenerating = "generating"
+	EventSkipped    = "fake_skipped"
+	EventWritten    = "file_written"
+	EventSwapped    = "mock_swapped"
+	EventError      = "error"
+)
+
+// Event is a single progress event of registration or generation
+type Event struct {
+	Level      Level  `json:"level"`
+	Kind       string `json:"kind"`
+	Component  string `json:"component,omitempty"`
+	Dependency string `json:"dependency,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+func (e Event) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", e.Level, e.Kind)
+	if e.Component != "" {
+		fmt.Fprintf(&b, " component=%s", e.Component)
+	}
+	if e.Dependency != "" {
+		fmt.Fprintf(&b, " dependency=%s", e.Dependency)
+	}
+	if e.Path != "" {
+		fmt.Fprintf(&b, " path=%s", e.Path)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, " %s", e.Message)
+	}
+	return b.String()
+}
+
+// Reporter receives progress events, implementations must be safe for concurrent use
+type Reporter interface {
+	Report(e Event)
+}
+
+// ReporterFunc adapts a function to a Reporter
+type ReporterFunc func(e Event)
+
+// Report calls f(e)
+func (f ReporterFunc) Report(e Event) {
+	f(e)
+}
+
+// Silent discards all events
+var Silent Reporter = ReporterFunc(func(Event) {})
+
+// LogReporter prints events at or above level min to a logger
+func LogReporter(l *log.Logger, min Level) Reporter {
+	return ReporterFunc(func(e Event) {
+		if e.Level >= min {
+			l.Println(e)
+		}
+	})
+}
+
+// JSONReporter writes events at or above level min to w, one JSON object per line
+func JSONReporter(w io.Writer, min Level) Reporter {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return ReporterFunc(func(e Event) {
+		if e.Level < min {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(e)
+	})
+}
+
+// WithReporter sets the reporter receiving progress events, defaults to warnings and errors on stdout
+func WithReporter(r Reporter) Option {
+	return func(b *builder) {
+		b.Reporter = r
+	}
+}
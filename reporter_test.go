@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func Test_jsonReporter(t *testing.T) {
+	var out bytes.Buffer
+	files := generate(t, WithReporter(JSONReporter(&out, Debug)))
+	if len(files) != 1 {
+		t.Fatalf("number of files should have been %d, but was %d", 1, len(files))
+	}
+	kinds := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		e := Event{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("each line should have been a JSON event, but got %s", line)
+		}
+		kinds[e.Kind]++
+	}
+	if kinds[EventRegistered] != 2 {
+		t.Errorf("number of %s events should have been %d, but was %d", EventRegistered, 2, kinds[EventRegistered])
+	}
+	if kinds[EventResolved] == 0 {
+		t.Errorf("%s events should have been reported", EventResolved)
+	}
+	if kinds[EventGenerating] != 2 {
+		t.Errorf("number of %s events should have been %d, but was %d", EventGenerating, 2, kinds[EventGenerating])
+	}
+	if kinds[EventWritten] != 1 {
+		t.Errorf("number of %s events should have been %d, but was %d", EventWritten, 1, kinds[EventWritten])
+	}
+	if !strings.Contains(out.String(), `"level":"debug"`) {
+		t.Errorf("levels should have been reported by name")
+	}
+}
+
+func Test_logReporter(t *testing.T) {
+	var out bytes.Buffer
+	r := LogReporter(log.New(&out, "", 0), Warn)
+	r.Report(Event{Level: Info, Kind: EventWritten, Component: "L1", Path: "mocks_test.go"})
+	if out.Len() != 0 {
+		t.Errorf("events below the level should NOT have been printed, but got %s", out.String())
+	}
+	r.Report(Event{Level: Error, Kind: EventError, Component: "L1", Message: "failed"})
+	if out.String() != "error error component=L1 failed\n" {
+		t.Errorf("should have been '%s', but was '%s'", "error error component=L1 failed\n", out.String())
+	}
+}
+
+func Test_unfakedReported(t *testing.T) {
+	var out bytes.Buffer
+	m := New("mock", WithOutput(func(path string, src []byte) error { return nil }), WithReporter(JSONReporter(&out, Warn)))
+	m.Register([]fuse.Entry{{Name: "clock", Instance: &Clock{}}})
+	if errs := m.Generate(); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	e := Event{}
+	if err := json.Unmarshal(out.Bytes(), &e); err != nil || e.Kind != EventSkipped || !strings.Contains(e.Message, "Trace") {
+		t.Errorf("variadic Trace should have been reported once as %s, but got %s", EventSkipped, out.String())
+	}
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"testing"
+)
+
+func Test_sequenceAfterCalls(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := MockL1{}
+	m.LM1(1, 1)
+	m.LM1(2, 2)
+	m.LM1ReturnsSequence(MockL1_LM1_Results{R0: "first"}, MockL1_LM1_Results{R0: "second"})
+	for _, want := range []string{"first", "second"} {
+		if s, _ := m.LM1(3, 3); s != want {
+			t.Errorf("should have been %s, but was %s", want, s)
+		}
+	}
+}
+
+func Test_resetMocks(t *testing.T) {
+	m := MockL1{}
+	m.LM1ReturnsSequence(MockL1_LM1_Results{R0: "first"})
+	m.LM1(1, 1)
+	MockL1_LM1_Faults.Always(Fault{Panic: "boom"})
+	ResetMocks()
+	if MockL1_LM1 != nil || NumCalls("MockL1_LM1") != 0 || len(AllCalls()) != 0 {
+		t.Fatalf("stub and calls should have been cleared, but were %v and %d", MockL1_LM1 != nil, NumCalls("MockL1_LM1"))
+	}
+	m.LM1ReturnsSequence(MockL1_LM1_Results{R0: "fresh"})
+	if s, _ := m.LM1(1, 1); s != "fresh" {
+		t.Errorf("a fresh sequence should have started with its first result, but was %s", s)
+	}
+	ResetMocks()
+}
@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_returns(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	m := &MockL1{}
+	m.LM2ReturnsOnCall(1, "second", time.Second)
+	m.LM2ReturnsWhen(5, 0, "five", 5)
+	if s, _ := m.LM2(1, 0); s != "" {
+		t.Errorf("call without configured results should have returned zero values, but returned %s", s)
+	}
+	if s, d := m.LM2(1, 0); s != "second" || d != time.Second {
+		t.Errorf("second call should have returned second 1s, but returned %s %s", s, d)
+	}
+	if s, d := m.LM2(5, 0); s != "five" || d != 5 {
+		t.Errorf("call with matching arguments should have returned five 5ns, but returned %s %s", s, d)
+	}
+	var failure string
+	l1 := MockL1{Defaults: &Defaults{Fail: func(format string, args ...interface{}) {
+		failure = fmt.Sprintf(format, args...)
+	}}}
+	l1.LM1ReturnsSequence(MockL1_LM1_Results{R0: "only"})
+	if s, _ := l1.LM1(1, 2); s != "only" {
+		t.Errorf("first call should have returned only, but returned %s", s)
+	}
+	l1.LM1(1, 2)
+	if !strings.Contains(failure, "exhausted the sequence of 1 results") {
+		t.Errorf("call beyond the sequence should have failed, but got '%s'", failure)
+	}
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Snapshots selects the mock methods whose arguments are deep copied when a call is captured, so that call records
+// keep the state arguments had at call time. Generated mocks hold one (ArgSnapshots). Values implementing
+// context.Context, channels and funcs are never copied. The zero value copies nothing and is ready to use
+type Snapshots struct {
+	mu      sync.Mutex
+	all     bool
+	methods map[string]bool
+	skip    map[reflect.Type]bool
+}
+
+// All copies the arguments of every method
+func (s *Snapshots) All() *Snapshots {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.all = true
+	return s
+}
+
+// Methods copies the arguments of the methods with the given keys, e.g. MockL1_LM1
+func (s *Snapshots) Methods(keys ...string) *Snapshots {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.methods == nil {
+		s.methods = make(map[string]bool)
+	}
+	for _, k := range keys {
+		s.methods[k] = true
+	}
+	return s
+}
+
+// Skip keeps values of the types ptrs point to, and pointers to them, as they are, e.g. Skip((*sync.Mutex)(nil))
+func (s *Snapshots) Skip(ptrs ...interface{}) *Snapshots {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.skip == nil {
+		s.skip = make(map[reflect.Type]bool)
+	}
+	for _, p := range ptrs {
+		s.skip[reflect.TypeOf(p).Elem()] = true
+	}
+	return s
+}
+
+// Reset stops copying arguments
+func (s *Snapshots) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.all = false
+	s.methods = nil
+	s.skip = nil
+}
+
+// Copy returns deep copies of the arguments of a call of the method key when it is selected, args otherwise
+func (s *Snapshots) Copy(key string, args []interface{}) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.all && !s.methods[key] {
+		return args
+	}
+	c := &copier{skip: s.skip, copies: make(map[copyKey]reflect.Value)}
+	copies := make([]interface{}, 0, len(args))
+	for _, a := range args {
+		v := c.copy(reflect.ValueOf(a))
+		if !v.IsValid() {
+			copies = append(copies, nil)
+			continue
+		}
+		copies = append(copies, v.Interface())
+	}
+	return copies
+}
+
+// copier deep copies values, values reachable more than once are copied once so cycles are preserved
+type copier struct {
+	skip   map[reflect.Type]bool
+	copies map[copyKey]reflect.Value
+}
+
+// copyKey identifies a pointer, map or slice that was copied
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+func (c *copier) copy(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	t := v.Type()
+	if c.skip[t] || t.Kind() != reflect.Interface && t.Implements(contextType) {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || c.skip[t.Elem()] {
+			return v
+		}
+		k := copyKey{ptr: v.Pointer(), typ: t}
+		if p, ok := c.copies[k]; ok {
+			return p
+		}
+		p := reflect.New(t.Elem())
+		c.copies[k] = p
+		p.Elem().Set(c.copy(v.Elem()))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		i := reflect.New(t).Elem()
+		i.Set(c.copy(v.Elem()))
+		return i
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		k := copyKey{ptr: v.Pointer(), typ: t}
+		if m, ok := c.copies[k]; ok {
+			return m
+		}
+		m := reflect.MakeMapWithSize(t, v.Len())
+		c.copies[k] = m
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(c.copy(iter.Key()), c.copy(iter.Value()))
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		k := copyKey{ptr: v.Pointer(), typ: t, len: v.Len()}
+		if s, ok := c.copies[k]; ok {
+			return s
+		}
+		s := reflect.MakeSlice(t, v.Len(), v.Len())
+		c.copies[k] = s
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			a.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return a
+	case reflect.Struct:
+		if !v.CanAddr() {
+			// fields of an addressable struct can be read even when unexported
+			tmp := reflect.New(t).Elem()
+			tmp.Set(v)
+			v = tmp
+		}
+		s := reflect.New(t).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			exported(s.Field(i)).Set(c.copy(exported(v.Field(i))))
+		}
+		return s
+	}
+	// basic values are copied by value, channels, funcs and unsafe pointers are shared
+	return v
+}
+
+// exported returns a settable view of an addressable, possibly unexported, struct field
+func exported(f reflect.Value) reflect.Value {
+	if f.CanSet() {
+		return f
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type node struct {
+	name  string
+	next  *node
+	tags  []string
+	attrs map[string]interface{}
+	mu    *sync.Mutex
+}
+
+func Test_snapshots(t *testing.T) {
+	n := &node{name: "a", tags: []string{"x"}, attrs: map[string]interface{}{"k": []int{1}}, mu: &sync.Mutex{}}
+	n.next = n
+	ctx := context.WithValue(context.Background(), ctxKey("user"), "bob")
+	s := &Snapshots{}
+	args := []interface{}{n, ctx, nil}
+	if got := s.Copy("MockL1_LM1", args); got[0] != n {
+		t.Errorf("arguments should NOT have been copied before snapshots are selected")
+	}
+	s.Methods("MockL1_LM1").Skip((*sync.Mutex)(nil))
+	got := s.Copy("MockL1_LM1", args)
+	n.name = "b"
+	n.tags[0] = "y"
+	n.attrs["k"].([]int)[0] = 2
+	c := got[0].(*node)
+	if c == n || c.name != "a" || c.tags[0] != "x" || c.attrs["k"].([]int)[0] != 1 {
+		t.Errorf("copy should have kept the state at call time, but was %+v", c)
+	}
+	if c.next != c {
+		t.Errorf("copy should have kept the cycle of the original")
+	}
+	if c.mu != n.mu || got[1] != ctx || got[2] != nil {
+		t.Errorf("skipped types, contexts and nil should NOT have been copied")
+	}
+	if got := s.Copy("MockL1_LM2", args); got[0] != n {
+		t.Errorf("arguments of unselected methods should NOT have been copied")
+	}
+	s.Reset()
+	if got := s.All().Copy("MockL1_LM2", args); got[0] == n {
+		t.Errorf("arguments of all methods should have been copied")
+	}
+}
@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"testing"
+)
+
+func Test_spy(t *testing.T) {
+	t.Cleanup(ResetMocks)
+	MockL3_LM3 = func(i1 int, f2 float32) string { return "l3" }
+	m := NewMockL2Spy(&L2{Il3: MockL3{}})
+	if s := m.LM21(1, 2); s != "l3  return from LM1" {
+		t.Errorf("unstubbed call should have been delegated to the real component, but returned %s", s)
+	}
+	MockL2_LM21 = func(i1 int, f2 float32) string { return "stubbed" }
+	if s := m.LM21(1, 2); s != "stubbed" {
+		t.Errorf("stubbed call should have returned %s, but returned %s", "stubbed", s)
+	}
+}
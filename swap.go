@@ -0,0 +1,106 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+var (
+	factoriesMu sync.Mutex
+	// factories make the generated mocks of components, keyed by the package path and name of the component
+	factories = make(map[string]func() interface{})
+)
+
+// RegisterMockFactory makes factory provide the mock of the component name, whose struct is declared in the package
+// with import path pkgPath, to Swap. Generated mocks register themselves
+func RegisterMockFactory(pkgPath, name string, factory func() interface{}) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[factoryKey(pkgPath, name)] = factory
+}
+
+func factoryKey(pkgPath, name string) string {
+	return pkgPath + " " + name
+}
+
+// Swap returns a copy of entries in which the components with the given names are replaced by new instances of
+// their generated mocks, so that fuse wires the object graph with only those components mocked. Swap is guarded
+// by the builder's Policy. It fails for names without an entry or a registered mock, and for mocks that fuse
+// could not inject into the `_fuse` fields referring to them, e.g. fields of a struct pointer type
+func (b *builder) Swap(entries []fuse.Entry, names ...string) ([]fuse.Entry, []error) {
+	errs := make([]error, 0)
+	_, fn, _, _ := runtime.Caller(1)
+	if err := b.Policy(fn); err != nil {
+		b.Reporter.Report(Event{Level: Error, Kind: EventError, Message: err.Error()})
+		return entries, append(errs, err)
+	}
+	swapped := make([]fuse.Entry, len(entries))
+	copy(swapped, entries)
+	mocks := make(map[string]reflect.Type)
+	for _, name := range names {
+		i := entryIndex(entries, name)
+		factoriesMu.Lock()
+		factory, ok := factories[factoryKey(entryPkgPath(entries, i), name)]
+		factoriesMu.Unlock()
+		switch {
+		case i < 0:
+			errs = append(errs, fmt.Errorf("entry [%s] to swap is not in the list", name))
+		case !ok:
+			errs = append(errs, fmt.Errorf("entry [%s] has no generated mock to swap in", name))
+		default:
+			m := factory()
+			swapped[i].Instance = m
+			mocks[name] = reflect.TypeOf(m)
+			b.Reporter.Report(Event{Level: Info, Kind: EventSwapped, Component: name, Message: mocks[name].String()})
+		}
+	}
+	for _, e := range swapped {
+		t := reflect.TypeOf(e.Instance)
+		if _, ok := mocks[e.Name]; ok || t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		for i := 0; i < t.Elem().NumField(); i++ {
+			f := t.Elem().Field(i)
+			dep, ok := f.Tag.Lookup("_fuse")
+			if m, swap := mocks[dep]; ok && swap && !m.AssignableTo(f.Type) {
+				errs = append(errs, fmt.Errorf("mock %s of [%s] cannot be injected into field %s %s of [%s]", m, dep, f.Name, f.Type, e.Name))
+			}
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+	for _, err := range errs {
+		b.Reporter.Report(Event{Level: Error, Kind: EventError, Message: err.Error()})
+	}
+	return swapped, errs
+}
+
+// entryPkgPath is the import path of the package declaring the struct of the ith entry
+func entryPkgPath(entries []fuse.Entry, i int) string {
+	if i < 0 {
+		return ""
+	}
+	t := reflect.TypeOf(entries[i].Instance)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.PkgPath()
+}
+
+func entryIndex(entries []fuse.Entry, name string) int {
+	for i, e := range entries {
+		if e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
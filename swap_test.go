@@ -0,0 +1,101 @@
+package mock
+
+import (
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func Test_swap(t *testing.T) {
+	RegisterMockFactory(runtimePath, "Il2", func() interface{} { return &MockL2{} })
+	RegisterMockFactory(runtimePath, "svc3", func() interface{} { return &MockL2{} })
+	// a component of the same name in another package keeps its own mock
+	RegisterMockFactory("example.com/other", "Il2", func() interface{} { return &MockL1{} })
+	m := New("mock", WithReporter(Silent))
+	entries := []fuse.Entry{{Name: "l1", Instance: &L1{}}, {Name: "Il2", Instance: &L2{}}}
+	swapped, errs := m.Swap(entries, "Il2")
+	if len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	if _, ok := entries[1].Instance.(*L2); !ok {
+		t.Errorf("entries should NOT have been modified")
+	}
+	if _, ok := swapped[1].Instance.(*MockL2); !ok || swapped[0].Instance != entries[0].Instance {
+		t.Errorf("only Il2 should have been swapped, but was %#v", swapped)
+	}
+
+	f := fuse.New()
+	if errs := f.Register(swapped); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	if errs := f.Wire(); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	l1 := f.Find("l1").(*L1)
+	MockL2_LM21 = func(i1 int, f2 float32) string { return "mocked" }
+	defer func() { MockL2_LM21 = nil }()
+	if s := l1.Il2.LM21(1, 2); s != "mocked" {
+		t.Errorf("wired dependency should have been the mock, but returned %s", s)
+	}
+
+	entries = []fuse.Entry{{Name: "svc1", Instance: &Svc1{}}, {Name: "svc3", Instance: &Svc3{}}}
+	_, errs = m.Swap(entries, "svc3", "svc4", "l1")
+	want := []string{
+		"entry [l1] to swap is not in the list",
+		"entry [svc4] to swap is not in the list",
+		"mock *mock.MockL2 of [svc3] cannot be injected into field S3 *mock.Svc3 of [svc1]",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errors should have been %v, but were %v", want, errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error should have been '%s', but was '%s'", want[i], err)
+		}
+	}
+	entries = append(entries, fuse.Entry{Name: "nomock", Instance: &Svc2{}}, fuse.Entry{Name: "Il2", Instance: &httptest.ResponseRecorder{}})
+	for _, name := range []string{"nomock", "Il2"} {
+		if _, errs = m.Swap(entries, name); len(errs) != 1 || !strings.Contains(errs[0].Error(), "no generated mock") {
+			t.Errorf("entry %s without mock in its package should have failed, but was %v", name, errs)
+		}
+	}
+
+	denied := New("mock", WithReporter(Silent), WithPolicy(func(string) error { return errors.New("denied") }))
+	if _, errs := denied.Swap(entries, "svc3"); len(errs) != 1 || errs[0].Error() != "denied" {
+		t.Errorf("policy should have denied Swap, but was %v", errs)
+	}
+}
+
+func Test_factoryPerName(t *testing.T) {
+	var first map[string]string
+	for i := 0; i < 10; i++ {
+		files := make(map[string]string)
+		m := New("mock", WithReporter(Silent), WithFileName("mock_%s_test.go"), WithOutput(func(path string, src []byte) error {
+			files[filepath.Base(path)] = string(src)
+			return nil
+		}))
+		m.Register([]fuse.Entry{{Name: "L1", Instance: &L1{}}, {Name: "Other", Instance: &L1{}}})
+		if errs := m.Generate(); len(errs) != 0 {
+			t.Fatalf("no errors expected, but got %v", errs)
+		}
+		if first == nil {
+			first = files
+		} else if !reflect.DeepEqual(files, first) {
+			t.Fatalf("output should have been the same every time")
+		}
+	}
+	src, ok := first["mock_l1_test.go"]
+	if len(first) != 1 || !ok {
+		t.Fatalf("L1 should have been generated under its first name, but got %d files", len(first))
+	}
+	for _, name := range []string{"L1", "Other"} {
+		if want := `RegisterMockFactory("` + runtimePath + `", "` + name + `", func() interface{} {`; !strings.Contains(src, want) {
+			t.Errorf("should have contained '%s'", want)
+		}
+	}
+}
@@ -0,0 +1,447 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// The data model below is what mock templates are executed with. The main template "file" renders a File,
+// it is composed of the blocks "header" (File), "recorder" (File), "defaults" (File), "mock" (MockType) and
+// "method" (Method), any of which can be overridden with WithTemplate, WithTemplateFiles or WithTemplateFS.
+
+// File is one generated file, holding the mocks of a component and its dependencies
+type File struct {
+	// Package is the name of the package the mocks are generated into
+	Package string
+	// Component is the name of the component the file is generated for, the first in name order when several
+	// share the file
+	Component string
+	// Imports are the import paths needed by the mocks and the blocks of the flavor, sorted
+	Imports []string
+	// Record is set when the call recorder is to be emitted
+	Record bool
+	// Shared is set on the first file generated into a package, which declares the recorder and defaults shared by
+	// the mocks of the package
+	Shared bool
+	// Flavor is the style of the generated mocks
+	Flavor string
+	// Runtime qualifies the support types of this package in generated code, "mock." or blank when
+	// generating into this package
+	Runtime string
+	// RuntimePath is the import path of this package
+	RuntimePath string
+	// Mocks are sorted by name
+	Mocks []*MockType
+	// types are the packages of the mocked structs, their fields included, and sigs those of the method signatures
+	types, sigs []string
+}
+
+// MockType is the mock of a single component
+type MockType struct {
+	// Name of the mock type, e.g. MockL1
+	Name string
+	// Struct is the name of the mocked struct, e.g. L1
+	Struct string
+	// Component is the registered name of the mocked component
+	Component string
+	// Components are the names the mocked struct is registered under, Component first
+	Components []string
+	// PkgPath is the import path of the package of the mocked component
+	PkgPath string
+	// Real is the mocked struct as referenced from the generated package
+	Real string
+	// Spy is set when the mock delegates to a real instance unless a stub is set
+	Spy bool
+	// Fixture is set when the mock replays fixtures, a recording wrapper (e.g. MockL1Recording) is emitted with it
+	Fixture bool
+	// File is the file the mock is generated into
+	File    *File
+	Fields  []*Field
+	Methods []*Method
+	// Fakes are the methods standing in for func fields, also in Methods
+	Fakes []*Method
+}
+
+// Field is a field of a mocked struct
+type Field struct {
+	Name string
+	Type string
+	// Embedded is set for embedded fields, the mock embeds them too so promoted fields and methods remain
+	Embedded bool
+}
+
+// Method is a mocked method
+type Method struct {
+	// Name of the method
+	Name string
+	// Func is the name of the stub func type, the method name unless another mock of the file has a method of that
+	// name with a different signature, then e.g. MockL1_LM1_Func
+	Func string
+	// DeclareFunc is set on the first method of the file with stub func type Func
+	DeclareFunc bool
+	// Mock is the mock the method belongs to
+	Mock *MockType
+	// Field is the func field of the component the method fakes, e.g. Now for FakeNow, blank for methods
+	Field string
+	// Stub is the name of the package variable holding the stub, e.g. MockL1_LM1
+	Stub string
+	// Receiver is the receiver prefix, "v " for value and "p *" for pointer receivers
+	Receiver string
+	// Recv is the name of the receiver variable, "v" or "p"
+	Recv string
+	// Params are the input parameters as written in a signature, e.g. "a1 int,a2 float32"
+	Params string
+	// WhenParams are Params with a variadic input taken as a slice, so results can follow it
+	WhenParams string
+	// Results are the output parameters as written in a signature, e.g. "(string,*int)"
+	Results string
+	// Ctx is the first input parameter of type context.Context, e.g. "a1", blank when there is none
+	Ctx string
+	// Writable is set when an input parameter is Writable
+	Writable bool
+	// Feed is the first output parameter of a channel type, nil when there is none
+	Feed *Param
+	// Err is the output parameter of type error, e.g. "ret1", blank when there is none
+	Err string
+	// Names are the input parameter names as written in a call, e.g. " a1, a2"
+	Names string
+	// Args is a slice literal of the input parameters, e.g. "[]interface{}{a1 ,a2 }"
+	Args string
+	In   []*Param
+	Out  []*Param
+}
+
+// Param is an input or output parameter of a mocked method, output parameters are named ret0, ret1...
+type Param struct {
+	Name string
+	// Field is the name of the parameter as a field of a recorded call, e.g. A1 or Ret0
+	Field string
+	Type  string
+	Ptr   bool
+	// Error is set for parameters of type error
+	Error bool
+	// Context is set for parameters of type context.Context
+	Context bool
+	// Writable is set for pointer, slice and map input parameters, values can be written into them
+	Writable bool
+	// Elem is the element type of channel parameters
+	Elem string
+	// Callback is set for input parameters of non variadic func types, FuncIn are their parameters named a0, a1...
+	Callback bool
+	FuncIn   []*Param
+}
+
+// runtimePath is the import path of this package, generated code uses its support types qualified by runtimeName
+var runtimePath = reflect.TypeOf(Faults{}).PkgPath()
+
+var runtimeName = strings.TrimSuffix(reflect.TypeOf(Faults{}).String(), ".Faults")
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// tmplSource is a user supplied template or set of blocks
+type tmplSource struct {
+	name string
+	text string
+}
+
+// WithTemplate overrides the main template or named blocks with text, blocks are defined with {{define "name"}}
+func WithTemplate(name, text string) Option {
+	return func(b *builder) {
+		b.Templates = append(b.Templates, tmplSource{name: name, text: text})
+	}
+}
+
+// WithTemplateFiles overrides the main template or named blocks with the contents of files
+func WithTemplateFiles(files ...string) Option {
+	return func(b *builder) {
+		for _, f := range files {
+			text, err := ioutil.ReadFile(f)
+			if err != nil {
+				b.tmplErrors = append(b.tmplErrors, err)
+				continue
+			}
+			b.Templates = append(b.Templates, tmplSource{name: filepath.Base(f), text: string(text)})
+		}
+	}
+}
+
+// WithTemplateFS overrides the main template or named blocks with the files of fsys matching patterns,
+// typically an embed.FS
+func WithTemplateFS(fsys fs.FS, patterns ...string) Option {
+	return func(b *builder) {
+		for _, p := range patterns {
+			files, err := fs.Glob(fsys, p)
+			if err != nil {
+				b.tmplErrors = append(b.tmplErrors, err)
+				continue
+			}
+			if len(files) == 0 {
+				b.tmplErrors = append(b.tmplErrors, fmt.Errorf("pattern %s matches no template files", p))
+			}
+			for _, f := range files {
+				text, err := fs.ReadFile(fsys, f)
+				if err != nil {
+					b.tmplErrors = append(b.tmplErrors, err)
+					continue
+				}
+				b.Templates = append(b.Templates, tmplSource{name: f, text: string(text)})
+			}
+		}
+	}
+}
+
+// blocks are the templates a user may override
+var blocks = []string{"file", "header", "recorder", "defaults", "mock", "method"}
+
+// parseTemplates parses the default template followed by the flavor's and the user's overrides
+func (b *builder) parseTemplates(f Flavor) (*template.Template, error) {
+	if len(b.tmplErrors) > 0 {
+		return nil, b.tmplErrors[0]
+	}
+	funcMap := template.FuncMap{
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"union": union,
+	}
+	tmpl, err := template.New("letter").Funcs(funcMap).Parse(letter)
+	if err != nil {
+		return nil, fmt.Errorf("parsing: %s", err)
+	}
+	text, ok := flavors[f]
+	if !ok {
+		return nil, fmt.Errorf("unknown flavor %s", f)
+	}
+	if _, err := tmpl.New(string(f)).Parse(text); err != nil {
+		return nil, fmt.Errorf("parsing: %s", err)
+	}
+	for _, src := range b.Templates {
+		if _, err := tmpl.New(src.name).Parse(src.text); err != nil {
+			return nil, fmt.Errorf("parsing: %s", err)
+		}
+	}
+	for _, name := range blocks {
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("template %s is not defined", name)
+		}
+	}
+	return tmpl, nil
+}
+
+// fileData derives the template data model from the type information of a file
+func (b *builder) fileData(ginfo *genInfo) *File {
+	f := &File{Package: ginfo.EnclosingType.Pkg, Component: ginfo.EnclosingType.Name, Record: ginfo.Record,
+		Shared: ginfo.Shared, Flavor: string(b.flavor(ginfo.EnclosingType.Name)), RuntimePath: runtimePath}
+	if ginfo.EnclosingType.PkgPath != runtimePath {
+		f.Runtime = runtimeName + "."
+	}
+	pkg := ginfo.EnclosingType.PkgPath
+	for _, imp := range strings.Fields(printImports(ginfo.EnclosedTypes, pkg)) {
+		f.types = append(f.types, strings.Trim(imp, `"`))
+	}
+	seen := make(map[string]bool)
+	for _, info := range ginfo.EnclosedTypes {
+		if seen[info.MockName] {
+			continue
+		}
+		seen[info.MockName] = true
+		m := &MockType{Name: info.MockName, Struct: info.StructName, Component: info.Name, Components: info.Names,
+			PkgPath: info.PkgPath, File: f, Real: typeName(info.Typ, pkg), Spy: b.Spies[""] || b.Spies[info.Name],
+			Fixture: b.Fixtures[""] || b.Fixtures[info.Name]}
+		for _, fi := range info.Fields {
+			m.Fields = append(m.Fields, &Field{Name: fi.Name, Type: typeName(fi.Typ, pkg), Embedded: fi.StructField.Anonymous})
+		}
+		for _, fn := range info.Funcs {
+			md := methodData(m, fn, pkg)
+			m.Methods = append(m.Methods, md)
+			for _, p := range fn.Params[1:] {
+				f.sigs = pkgPaths(p.Typ, f.sigs)
+			}
+			if md.Field != "" {
+				m.Fakes = append(m.Fakes, md)
+			}
+		}
+		if (m.Spy || m.Fixture) && info.PkgPath != pkg {
+			// Spied and the recording wrapper refer to the real component
+			f.types = append(f.types, info.PkgPath)
+		}
+		f.Mocks = append(f.Mocks, m)
+	}
+	for _, imp := range union(Flavor(f.Flavor).imports(f)) {
+		if imp != pkg {
+			f.Imports = append(f.Imports, imp)
+		}
+	}
+	sort.Slice(f.Mocks, func(i, j int) bool {
+		return f.Mocks[i].Name < f.Mocks[j].Name
+	})
+	// mocks of embedding components share the names of promoted methods with the mocks of their dependencies
+	sigs := ginfo.Funcs
+	if sigs == nil {
+		sigs = make(map[string]string)
+	}
+	for _, m := range f.Mocks {
+		for _, md := range m.Methods {
+			sig := md.Params + md.Results
+			md.Func = md.Name
+			if s, ok := sigs[md.Name]; !ok {
+				sigs[md.Name] = sig
+				md.DeclareFunc = true
+			} else if s != sig {
+				md.Func = md.Stub + "_Func"
+				md.DeclareFunc = true
+			}
+		}
+	}
+	return f
+}
+
+// methodData derives the template data of a method generated into the package with import path pkg, printInParams
+// names the inputs so it goes first
+func methodData(m *MockType, fn *funcInfo, pkg string) *Method {
+	md := &Method{Name: fn.Name, Mock: m, Field: fn.Field, Stub: m.Name + "_" + fn.Name, Receiver: receiver(fn)}
+	md.Recv = strings.TrimSpace(strings.TrimSuffix(md.Receiver, "*"))
+	md.Params = printInParams(fn.Params, pkg, true)
+	md.WhenParams = printInParams(fn.Params, pkg, false)
+	md.Results = printOutParams(fn.Params, pkg)
+	md.Names = printInNames(fn.Params)
+	md.Args = paramSlice(fn.Params)
+	for i, p := range fn.Params {
+		if p.Input && i > 0 {
+			in := &Param{Name: p.InName, Field: strings.ToUpper(p.InName[:1]) + p.InName[1:], Type: typeName(p.Typ, pkg), Ptr: p.Ptr, Context: p.Typ == contextType}
+			switch p.Typ.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				in.Writable = true
+				md.Writable = true
+			case reflect.Func:
+				in.Callback = !p.Typ.IsVariadic()
+				for j := 0; in.Callback && j < p.Typ.NumIn(); j++ {
+					a := &Param{Name: "a" + strconv.Itoa(j), Type: typeName(p.Typ.In(j), pkg)}
+					in.FuncIn = append(in.FuncIn, a)
+				}
+			}
+			if in.Context && md.Ctx == "" {
+				md.Ctx = in.Name
+			}
+			md.In = append(md.In, in)
+		}
+		if !p.Input {
+			out := &Param{Name: "ret" + strconv.Itoa(len(md.Out)), Type: typeName(p.Typ, pkg), Ptr: p.Ptr,
+				Error: p.Typ == errorType}
+			out.Field = "Ret" + strconv.Itoa(len(md.Out))
+			if p.Typ.Kind() == reflect.Chan {
+				out.Elem = typeName(p.Typ.Elem(), pkg)
+				if md.Feed == nil {
+					md.Feed = out
+				}
+			}
+			if out.Error {
+				md.Err = "ret" + strconv.Itoa(len(md.Out))
+			}
+			md.Out = append(md.Out, out)
+		}
+	}
+	return md
+}
+
+// union returns the sorted, distinct strings of list and add, e.g. imports
+func union(list []string, add ...string) []string {
+	seen := make(map[string]bool)
+	u := make([]string, 0)
+	for _, s := range append(append([]string{}, list...), add...) {
+		if !seen[s] {
+			seen[s] = true
+			u = append(u, s)
+		}
+	}
+	sort.Strings(u)
+	return u
+}
+
+// pkgPaths appends the import paths of the named types t is made of, the packages typeName refers to
+func pkgPaths(t reflect.Type, paths []string) []string {
+	if t.Name() != "" {
+		if t.PkgPath() != "" {
+			paths = append(paths, t.PkgPath())
+		}
+		return paths
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		return pkgPaths(t.Elem(), paths)
+	case reflect.Map:
+		return pkgPaths(t.Elem(), pkgPaths(t.Key(), paths))
+	case reflect.Func:
+		for i := 0; i < t.NumIn(); i++ {
+			paths = pkgPaths(t.In(i), paths)
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			paths = pkgPaths(t.Out(i), paths)
+		}
+	}
+	return paths
+}
+
+// typeName prints t as referenced from the package with import path pkg, the types of that package are not qualified
+func typeName(t reflect.Type, pkg string) string {
+	if t.Name() != "" {
+		if t.PkgPath() != "" && t.PkgPath() == pkg {
+			return t.Name()
+		}
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeName(t.Elem(), pkg)
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem(), pkg)
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + typeName(t.Elem(), pkg)
+	case reflect.Map:
+		return "map[" + typeName(t.Key(), pkg) + "]" + typeName(t.Elem(), pkg)
+	case reflect.Chan:
+		elem := typeName(t.Elem(), pkg)
+		switch {
+		case t.ChanDir() == reflect.RecvDir:
+			return "<-chan " + elem
+		case t.ChanDir() == reflect.SendDir:
+			return "chan<- " + elem
+		case t.Elem().Kind() == reflect.Chan && t.Elem().ChanDir() == reflect.RecvDir:
+			// chan (<-chan T) is not chan<- (chan T)
+			return "chan (" + elem + ")"
+		}
+		return "chan " + elem
+	case reflect.Func:
+		in := make([]string, 0, t.NumIn())
+		for i := 0; i < t.NumIn(); i++ {
+			if t.IsVariadic() && i == t.NumIn()-1 {
+				in = append(in, "..."+typeName(t.In(i).Elem(), pkg))
+				continue
+			}
+			in = append(in, typeName(t.In(i), pkg))
+		}
+		out := make([]string, 0, t.NumOut())
+		for i := 0; i < t.NumOut(); i++ {
+			out = append(out, typeName(t.Out(i), pkg))
+		}
+		s := "func(" + strings.Join(in, ", ") + ")"
+		switch len(out) {
+		case 0:
+			return s
+		case 1:
+			return s + " " + out[0]
+		}
+		return s + " (" + strings.Join(out, ", ") + ")"
+	}
+	// struct and interface literals
+	return t.String()
+}
@@ -0,0 +1,227 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/rvauradkar1/fuse"
+)
+
+func Test_fileData(t *testing.T) {
+	b := New("mock").(*builder)
+	info := populateInfo(Component{Name: "L1", Instance: &L1{}})
+	info.MockName = "MockL1"
+	ginfo := genInfo{EnclosingType: info, EnclosedTypes: map[reflect.Type]*typeInfo{info.Typ: info}, Record: true, Shared: true}
+	f := b.fileData(&ginfo)
+	if f.Package != "mock" || f.Component != "L1" || !f.Record {
+		t.Errorf("file should have been for component L1 in package mock, but was %+v", f)
+	}
+	want := []string{"flag", "fmt", "reflect", "runtime", "strconv", "strings", "sync", "testing", "time"}
+	if !reflect.DeepEqual(f.Imports, want) {
+		t.Errorf("imports should have been %v, but were %v", want, f.Imports)
+	}
+	ginfo.Shared = false
+	if imports := b.fileData(&ginfo).Imports; !reflect.DeepEqual(imports, []string{"time"}) {
+		t.Errorf("imports without the recorder should have been %v, but were %v", []string{"time"}, imports)
+	}
+	m := f.Mocks[0]
+	if m.File != f || m.Name != "MockL1" || len(m.Methods) != 3 {
+		t.Fatalf("mock should have been MockL1 with 3 methods, but was %+v", m)
+	}
+	md := m.Methods[0]
+	if md.Stub != "MockL1_LM1" || md.Params != "a1 int,a2 float32" || md.Results != "(string,*int)" {
+		t.Errorf("method data was not correct, %+v", md)
+	}
+	if len(md.In) != 2 || md.In[1].Name != "a2" || md.In[1].Type != "float32" {
+		t.Errorf("inputs should have been a1 int, a2 float32, but were %+v %+v", md.In[0], md.In[1])
+	}
+	if len(md.Out) != 2 || md.Out[1].Type != "*int" || !md.Out[1].Ptr {
+		t.Errorf("outputs should have been string, *int, but were %+v %+v", md.Out[0], md.Out[1])
+	}
+	if m.Fields[4].Type != "L2" {
+		t.Errorf("own package qualifier should have been dropped, but was %s", m.Fields[4].Type)
+	}
+}
+
+func Test_templateBlocks(t *testing.T) {
+	method := `{{define "method"}}
+func ({{.Receiver}}{{.Mock.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	panic("{{.Stub}} is not implemented")
+}
+{{end}}`
+	files := generate(t, WithTemplateFiles(filepath.Join("testdata", "templates", "header.tmpl")), WithTemplate("method", method), WithFileName("mock_%s_test.go"))
+	s := files["mock_l1_test.go"]
+	if !strings.HasPrefix(s, "// Code generated by mockgen. DO NOT EDIT.\n\n//go:build !production\n") {
+		t.Errorf("header should have been overridden, but was %s", s)
+	}
+	if !strings.Contains(s, `panic("MockL1_LM1 is not implemented")`) {
+		t.Errorf("method should have been overridden, but was %s", s)
+	}
+	if !strings.Contains(s, "func NumCalls(name string) int") {
+		t.Errorf("recorder should have been kept")
+	}
+}
+
+func Test_headerCompiles(t *testing.T) {
+	files := compile(t, WithTemplateFiles(filepath.Join("testdata", "templates", "header.tmpl")))
+	for _, s := range files {
+		if !strings.HasPrefix(s, "// Code generated by mockgen. DO NOT EDIT.\n") {
+			t.Errorf("header should have been overridden, but was %s", s)
+		}
+	}
+}
+
+func Test_templateFS(t *testing.T) {
+	fsys := fstest.MapFS{"tmpl/file.tmpl": {Data: []byte(`{{define "file"}}package {{.Package}}
+{{range .Mocks}}// {{.Name}}
+{{end}}{{end}}`)}}
+	files := generate(t, WithTemplateFS(fsys, "tmpl/*.tmpl"), WithFileName("mock_%s_test.go"))
+	if files["mock_l1_test.go"] != "package mock\n\n// MockL1\n" {
+		t.Errorf("whole template should have been overridden, but was %q", files["mock_l1_test.go"])
+	}
+	if files["mock_l2_test.go"] != "package mock\n\n// MockL2\n" {
+		t.Errorf("whole template should have been overridden, but was %q", files["mock_l2_test.go"])
+	}
+}
+
+func Test_templateErrors(t *testing.T) {
+	m := New("mock", WithTemplate("bad.tmpl", "{{define \"mock\"}}\n{{.Name}\n{{end}}"), WithReporter(Silent))
+	errs := m.Generate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "bad.tmpl:2") {
+		t.Errorf("error should have pointed to bad.tmpl:2, but was %v", errs)
+	}
+	b := New("mock", WithTemplate("exec.tmpl", "{{define \"method\"}}\n{{.Missing}}{{end}}"), WithReporter(Silent)).(*builder)
+	b.Registry["L1"] = Component{Name: "L1", Instance: &L1{}, Basepath: os.TempDir()}
+	b.Output = func(string, []byte) error { return nil }
+	errs = b.Generate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "exec.tmpl:2") {
+		t.Errorf("error should have pointed to exec.tmpl:2, but was %v", errs)
+	}
+	m = New("mock", WithTemplateFiles("testdata/templates/missing.tmpl"), WithReporter(Silent))
+	if errs = m.Generate(); len(errs) != 1 {
+		t.Errorf("missing template file should have errored out")
+	}
+}
+
+func Test_typeName(t *testing.T) {
+	for _, c := range []struct {
+		t    reflect.Type
+		pkg  string
+		want string
+	}{
+		// the package name text is a suffix of context
+		{contextType, "example.com/text", "context.Context"},
+		{reflect.TypeOf(map[string][]*L1{}), runtimePath, "map[string][]*L1"},
+		{reflect.TypeOf(map[string][]*L1{}), "example.com/text", "map[string][]*mock.L1"},
+		{reflect.TypeOf([2]chan<- Message{}), runtimePath, "[2]chan<- Message"},
+		{reflect.TypeOf((chan (<-chan int))(nil)), runtimePath, "chan (<-chan int)"},
+		{reflect.TypeOf((func(context.Context, ...*L2) (<-chan Message, error))(nil)), runtimePath,
+			"func(context.Context, ...*L2) (<-chan Message, error)"},
+		{reflect.TypeOf((func(Message))(nil)), runtimePath, "func(Message)"},
+		{reflect.TypeOf((*interface{})(nil)).Elem(), runtimePath, "interface {}"},
+	} {
+		if s := typeName(c.t, c.pkg); s != c.want {
+			t.Errorf("should have been '%s', but was '%s'", c.want, s)
+		}
+		if s := typeName(c.t, ""); s != c.t.String() {
+			t.Errorf("should have been '%s' when qualified, but was '%s'", c.t, s)
+		}
+	}
+}
+
+func Test_pkgPaths(t *testing.T) {
+	typ := reflect.TypeOf((func(map[string][]*time.Time, ...chan<- context.Context) (*httptest.ResponseRecorder, error))(nil))
+	want := []string{"time", "context", "net/http/httptest"}
+	if paths := pkgPaths(typ, nil); !reflect.DeepEqual(paths, want) {
+		t.Errorf("should have been %v, but was %v", want, paths)
+	}
+}
+
+func Test_spyReal(t *testing.T) {
+	b := New("mock", WithSpy()).(*builder)
+	info := populateInfo(Component{Name: "L1", Instance: &L1{}})
+	info.MockName = "MockL1"
+	// mocks generated into a package named test, a suffix of httptest
+	info.PkgPath, info.Pkg = "example.com/test", "test"
+	rec := populateInfo(Component{Name: "rec", Instance: &httptest.ResponseRecorder{}})
+	rec.MockName = "MockResponseRecorder"
+	ginfo := genInfo{EnclosingType: info, EnclosedTypes: map[reflect.Type]*typeInfo{info.Typ: info, rec.Typ: rec}, Shared: true}
+	f := b.fileData(&ginfo)
+	real := make(map[string]string)
+	for _, m := range f.Mocks {
+		real[m.Name] = m.Real
+	}
+	want := map[string]string{"MockL1": "mock.L1", "MockResponseRecorder": "httptest.ResponseRecorder"}
+	if !reflect.DeepEqual(real, want) {
+		t.Errorf("spied types should have been %v, but were %v", want, real)
+	}
+	imports := union(f.Imports, "net/http/httptest", runtimePath)
+	if len(imports) != len(f.Imports) {
+		t.Errorf("imports should have contained the spied packages, but were %v", f.Imports)
+	}
+}
+
+func Test_embedded(t *testing.T) {
+	files := make(map[string]string)
+	m := New("mock", WithOutput(func(path string, src []byte) error {
+		files[filepath.Base(path)] = string(src)
+		return nil
+	}), WithFileName("mock_%s_test.go"))
+	m.Register([]fuse.Entry{{Name: "emb", Instance: &Emb{}}, {Name: "l3", Instance: &L3{}}})
+	if errs := m.Generate(); len(errs) != 0 {
+		t.Fatalf("no errors expected, but got %v", errs)
+	}
+	if deps := m.Graph().Edges["emb"]; len(deps) != 1 || deps[0] != "l3" {
+		t.Errorf("embedded interface should have been resolved to l3, but was %v", deps)
+	}
+	s := files["mock_emb_test.go"]
+	for _, want := range []string{
+		"type MockEmb struct {\n\tL2\n\tIl3\n\tName string\n",
+		"func (v MockEmb) LM21(a1 int, a2 float32) (ret0 string) {",
+		"func (v MockEmb) LM3(a1 int, a2 float32) (ret0 string) {",
+		"func (p *MockEmb) Own(a1 string) (ret0 string) {",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("should have contained '%s', but was %s", want, s)
+		}
+	}
+	if strings.Contains(s, "type MockL3 struct {") || !strings.Contains(files["mock_l3_test.go"], "type MockL3 struct {") {
+		t.Errorf("MockL3 should have been declared in its own file only")
+	}
+	if n := strings.Count(s, ") LM21("); n != 1 {
+		t.Errorf("promoted method should have been mocked %d time, but was %d", 1, n)
+	}
+}
+
+func Test_funcTypes(t *testing.T) {
+	b := New("mock").(*builder)
+	ginfo := genInfo{EnclosedTypes: make(map[reflect.Type]*typeInfo)}
+	for _, c := range []Component{{Name: "l1", Instance: &L1{}}, {Name: "l3", Instance: &L3{}}, {Name: "emb", Instance: &Emb{}}} {
+		info := populateInfo(c)
+		info.MockName = "Mock" + info.StructName
+		ginfo.EnclosedTypes[info.Typ] = info
+		if c.Name == "l1" {
+			ginfo.EnclosingType = info
+		}
+	}
+	funcs := make(map[string]string)
+	for _, m := range b.fileData(&ginfo).Mocks {
+		for _, md := range m.Methods {
+			if md.Name == "LM3" {
+				funcs[md.Stub] = fmt.Sprintf("%s %v", md.Func, md.DeclareFunc)
+			}
+		}
+	}
+	want := map[string]string{"MockEmb_LM3": "LM3 true", "MockL1_LM3": "MockL1_LM3_Func true", "MockL3_LM3": "LM3 false"}
+	if !reflect.DeepEqual(funcs, want) {
+		t.Errorf("stub func types should have been %v, but were %v", want, funcs)
+	}
+}